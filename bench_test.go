@@ -0,0 +1,325 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+// benchValues returns a representative value for each implemented type.
+func benchValues() []rbxattr.Value {
+	s := rbxattr.ValueString("The quick brown fox jumps over the lazy dog")
+	b := rbxattr.ValueBool(true)
+	f := rbxattr.ValueFloat(3.14159)
+	d := rbxattr.ValueDouble(2.718281828459045)
+	bc := rbxattr.ValueBrickColor(194)
+	numbers := make(rbxattr.ValueNumberSequence, 20)
+	colors := make(rbxattr.ValueColorSequence, 20)
+	for i := range numbers {
+		t := float32(i) / float32(len(numbers)-1)
+		numbers[i] = rbxattr.ValueNumberSequenceKeypoint{Time: t, Value: t * 2}
+		colors[i] = rbxattr.ValueColorSequenceKeypoint{Time: t, Value: rbxattr.ValueColor3{R: t, G: 1 - t, B: 0.5}}
+	}
+	return []rbxattr.Value{
+		&s,
+		&b,
+		&f,
+		&d,
+		&rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+		&rbxattr.ValueUDim2{
+			X: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+			Y: rbxattr.ValueUDim{Scale: 0.25, Offset: -50},
+		},
+		&bc,
+		&rbxattr.ValueColor3{R: 1, G: 0.5, B: 0.25},
+		&rbxattr.ValueVector2{X: 1, Y: 2},
+		&rbxattr.ValueVector3{X: 1, Y: 2, Z: 3},
+		&rbxattr.ValueCFrame{
+			Position: rbxattr.ValueVector3{X: 1, Y: 2, Z: 3},
+			Rotation: [9]float32{1, 0, 0, 0, 1, 0, 0, 0, 1},
+		},
+		&rbxattr.ValueCFrame{
+			Position: rbxattr.ValueVector3{X: 1, Y: 2, Z: 3},
+			Rotation: [9]float32{0.5, 0, 0, 0, 0.5, 0, 0, 0, 0.5},
+		},
+		&numbers,
+		&colors,
+		&rbxattr.ValueNumberRange{Min: 0, Max: 10},
+		&rbxattr.ValueRect{Min: rbxattr.ValueVector2{X: 0, Y: 0}, Max: rbxattr.ValueVector2{X: 1, Y: 1}},
+		&rbxattr.ValueFont{
+			Weight:       700,
+			Style:        1,
+			Family:       "rbxasset://fonts/families/SourceSansPro.json",
+			CachedFaceID: "rbxasset://fonts/SourceSansPro-BoldItalic.ttf",
+		},
+	}
+}
+
+func TestBenchValuesAllTypes(t *testing.T) {
+	found := map[rbxattr.Type]bool{}
+	for _, v := range benchValues() {
+		found[v.Type()] = true
+	}
+	for name, typ := range exportedTypes {
+		if !found[typ] {
+			t.Errorf("%s: no benchmark value", name)
+		}
+	}
+}
+
+// benchName returns the name of a benchmark for v.
+func benchName(v rbxattr.Value) string {
+	if cf, ok := v.(*rbxattr.ValueCFrame); ok && cf.Rotation[0] != 1 {
+		return "CFrameMatrix"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", v), "*rbxattr.Value")
+}
+
+// benchModel returns a model containing one entry for each benchmark value.
+func benchModel() rbxattr.Model {
+	var model rbxattr.Model
+	for _, v := range benchValues() {
+		model.Value = append(model.Value, rbxattr.Entry{Key: benchName(v), Value: v})
+	}
+	return model
+}
+
+func BenchmarkValueReadFrom(b *testing.B) {
+	for _, v := range benchValues() {
+		v := v
+		b.Run(benchName(v), func(b *testing.B) {
+			var buf bytes.Buffer
+			if _, err := v.WriteTo(&buf); err != nil {
+				b.Fatal(err)
+			}
+			data := buf.Bytes()
+			r := bytes.NewReader(data)
+			value := rbxattr.NewValue(v.Type())
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				if _, err := value.ReadFrom(r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValueWriteTo(b *testing.B) {
+	for _, v := range benchValues() {
+		v := v
+		b.Run(benchName(v), func(b *testing.B) {
+			n, err := v.WriteTo(ioutil.Discard)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(n)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := v.WriteTo(ioutil.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkModelReadFrom(b *testing.B) {
+	model := benchModel()
+	var buf bytes.Buffer
+	if _, err := model.WriteTo(&buf); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+	r := bytes.NewReader(data)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Reset(data)
+		var m rbxattr.Model
+		if _, err := m.ReadFrom(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkModelWriteTo(b *testing.B) {
+	model := benchModel()
+	n, err := model.WriteTo(ioutil.Discard)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(n)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := model.WriteTo(ioutil.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDecodeStrings(b *testing.B, asBytes bool) {
+	var dict rbxattr.ValueDictionary
+	for i := 0; i < 100; i++ {
+		s := rbxattr.ValueString(strings.Repeat(fmt.Sprint(i), 50))
+		dict = append(dict, rbxattr.Entry{Key: fmt.Sprintf("String%d", i), Value: &s})
+	}
+	var buf bytes.Buffer
+	if _, err := dict.WriteTo(&buf); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := rbxattr.NewDecoder(bytes.NewBuffer(data))
+		dec.StringsAsBytes = asBytes
+		if _, err := dec.Decode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeStrings(b *testing.B) {
+	b.Run("String", func(b *testing.B) { benchmarkDecodeStrings(b, false) })
+	b.Run("Bytes", func(b *testing.B) { benchmarkDecodeStrings(b, true) })
+}
+
+func BenchmarkModelWriteToBools(b *testing.B) {
+	var model rbxattr.Model
+	for i := 0; i < 5000; i++ {
+		v := rbxattr.ValueBool(i%3 == 0)
+		model.Value = append(model.Value, rbxattr.Entry{Key: fmt.Sprintf("Flag%d", i), Value: &v})
+	}
+	b.SetBytes(model.Size())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := model.WriteTo(ioutil.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDecodeKeys(b *testing.B, asBytes bool) {
+	var dict rbxattr.ValueDictionary
+	v := rbxattr.ValueBool(true)
+	for i := 0; i < 5000; i++ {
+		dict = append(dict, rbxattr.Entry{Key: fmt.Sprintf("SomewhatLongAttributeName%d", i), Value: &v})
+	}
+	var buf bytes.Buffer
+	if _, err := dict.WriteTo(&buf); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+	want := []byte("SomewhatLongAttributeName4999")
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := rbxattr.NewDecoder(bytes.NewReader(data))
+		if _, err := dec.BeginDictionary(); err != nil {
+			b.Fatal(err)
+		}
+		found := false
+		for {
+			var match bool
+			var err error
+			if asBytes {
+				var key []byte
+				key, _, err = dec.DecodeEntryBytes()
+				match = bytes.Equal(key, want)
+			} else {
+				var entry rbxattr.Entry
+				entry, err = dec.DecodeEntry()
+				match = entry.Key == string(want)
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+			found = found || match
+		}
+		if !found {
+			b.Fatal("key not found")
+		}
+	}
+}
+
+func BenchmarkDecodeKeys(b *testing.B) {
+	b.Run("String", func(b *testing.B) { benchmarkDecodeKeys(b, false) })
+	b.Run("Bytes", func(b *testing.B) { benchmarkDecodeKeys(b, true) })
+}
+
+func BenchmarkDecodeVectors(b *testing.B) {
+	var dict rbxattr.ValueDictionary
+	for i := 0; i < 1000; i++ {
+		f := float32(i)
+		dict = append(dict,
+			rbxattr.Entry{Key: fmt.Sprintf("Position%d", i), Value: &rbxattr.ValueVector3{X: f, Y: f, Z: f}},
+			rbxattr.Entry{Key: fmt.Sprintf("Color%d", i), Value: &rbxattr.ValueColor3{R: 1, G: 0.5, B: 0}},
+			rbxattr.Entry{Key: fmt.Sprintf("Size%d", i), Value: &rbxattr.ValueUDim2{X: rbxattr.ValueUDim{Scale: 1}}},
+		)
+	}
+	var buf bytes.Buffer
+	if _, err := dict.WriteTo(&buf); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var model rbxattr.Model
+		if _, err := model.ReadFrom(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDecodeInterned(b *testing.B, intern bool) {
+	const models = 100
+	var dict rbxattr.ValueDictionary
+	for i := 0; i < 20; i++ {
+		v := rbxattr.ValueString(fmt.Sprintf("EnumLikeValue%d", i%4))
+		dict = append(dict, rbxattr.Entry{Key: fmt.Sprintf("SomewhatLongAttributeName%d", i), Value: &v})
+	}
+	var buf bytes.Buffer
+	for i := 0; i < models; i++ {
+		if _, err := dict.WriteTo(&buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+	data := buf.Bytes()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := rbxattr.NewDecoder(bytes.NewReader(data))
+		dec.InternStrings = intern
+		for j := 0; j < models; j++ {
+			if _, err := dec.Decode(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkDecodeInterned(b *testing.B) {
+	b.Run("Plain", func(b *testing.B) { benchmarkDecodeInterned(b, false) })
+	b.Run("Interned", func(b *testing.B) { benchmarkDecodeInterned(b, true) })
+}
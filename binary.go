@@ -3,6 +3,7 @@ package rbxattr
 import (
 	"encoding/binary"
 	"io"
+	"io/ioutil"
 	"math"
 )
 
@@ -21,6 +22,19 @@ func numberDataSize(data interface{}) int {
 	return 0
 }
 
+// maxPrealloc is the largest number of elements preallocated for a length read
+// from the input, which may be over-declared. Larger lengths grow as elements
+// are actually read, so that a hostile length cannot exhaust memory.
+const maxPrealloc = 1024
+
+// preallocLen returns the capacity to preallocate for length elements.
+func preallocLen(length uint32) int {
+	if length > maxPrealloc {
+		return maxPrealloc
+	}
+	return int(length)
+}
+
 // Reader wrapper that keeps track of the number of bytes written.
 type binaryReader struct {
 	r   io.Reader
@@ -59,6 +73,8 @@ func (br *binaryReader) Add(n int64, err error) (failed bool) {
 	return false
 }
 
+// Bytes reads exactly len(p) bytes into p. A reader may return the final
+// bytes together with io.EOF; the read succeeds if p was filled.
 func (br *binaryReader) Bytes(p []byte) (failed bool) {
 	if br.err != nil {
 		return true
@@ -74,6 +90,106 @@ func (br *binaryReader) Bytes(p []byte) (failed bool) {
 	return false
 }
 
+// maxChunk is the largest number of bytes allocated at once by Buffer before
+// the bytes preceding them have been read.
+const maxChunk = 64 << 10
+
+// Buffer reads length bytes into *buf, reusing its capacity if sufficient.
+// Otherwise, the buffer grows in chunks as bytes are actually read, so that an
+// over-declared length cannot exhaust memory. *buf is set to the bytes read.
+func (br *binaryReader) Buffer(buf *[]byte, length uint32) (failed bool) {
+	if br.err != nil {
+		return true
+	}
+	b := (*buf)[:0]
+	if uint64(cap(b)) >= uint64(length) {
+		b = b[:length]
+		if br.Bytes(b) {
+			return true
+		}
+		*buf = b
+		return false
+	}
+	for uint64(len(b)) < uint64(length) {
+		start := len(b)
+		n := maxChunk
+		if rest := uint64(length) - uint64(start); rest < maxChunk {
+			n = int(rest)
+		}
+		if cap(b)-start < n {
+			size := 2*cap(b) + n
+			if uint64(size) > uint64(length) {
+				size = int(length)
+			}
+			grown := make([]byte, start, size)
+			copy(grown, b)
+			b = grown
+		}
+		b = b[:start+n]
+		if br.Bytes(b[start:]) {
+			if br.err == io.EOF && start > 0 {
+				br.err = io.ErrUnexpectedEOF
+			}
+			return true
+		}
+	}
+	*buf = b
+	return false
+}
+
+// Components reads p, which holds consecutive 4-byte components, in a single
+// read. If the read fails, the failing component is at index N()/4, relative
+// to the start of the read. As when reading each component separately, the
+// error is io.EOF if the read ended between components.
+func (br *binaryReader) Components(p []byte) (failed bool) {
+	start := br.n
+	if br.Bytes(p) {
+		if br.err == io.ErrUnexpectedEOF && (br.n-start)%4 == 0 {
+			br.err = io.EOF
+		}
+		return true
+	}
+	return false
+}
+
+// float32At decodes a float32 from the first 4 bytes of b.
+func float32At(b []byte) float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(b))
+}
+
+// int32At decodes an int32 from the first 4 bytes of b.
+func int32At(b []byte) int32 {
+	return int32(binary.LittleEndian.Uint32(b))
+}
+
+// unreadLen returns the number of unread bytes remaining in r, if r reports
+// it, as do bytes.Reader, bytes.Buffer, and strings.Reader.
+func unreadLen(r io.Reader) (n int64, ok bool) {
+	if l, ok := r.(interface{ Len() int }); ok {
+		return int64(l.Len()), true
+	}
+	return 0, false
+}
+
+// Skip reads and discards n bytes.
+func (br *binaryReader) Skip(n int64) (failed bool) {
+	if br.err != nil {
+		return true
+	}
+
+	var m int64
+	m, br.err = io.CopyN(ioutil.Discard, br.r, n)
+	br.n += m
+	if br.err == io.EOF && m > 0 {
+		br.err = io.ErrUnexpectedEOF
+	}
+
+	if br.err != nil {
+		return true
+	}
+	return false
+}
+
 func (br *binaryReader) Number(data interface{}) (failed bool) {
 	if br.err != nil {
 		return true
@@ -125,8 +241,8 @@ func (br *binaryReader) String(data *string) (failed bool) {
 	if br.Number(&length) {
 		return true
 	}
-	s := make([]byte, length)
-	if br.Bytes(s) {
+	var s []byte
+	if br.Buffer(&s, length) {
 		return true
 	}
 	*data = string(s)
@@ -180,11 +296,11 @@ func (bw *binaryWriter) Bytes(p []byte) (failed bool) {
 	var n int
 	n, bw.err = bw.w.Write(p)
 	bw.n += int64(n)
-	if n < len(p) {
-		return true
+	if bw.err == nil && n < len(p) {
+		bw.err = io.ErrShortWrite
 	}
 
-	return false
+	return bw.err != nil
 }
 
 func (bw *binaryWriter) Number(data interface{}) (failed bool) {
@@ -0,0 +1,210 @@
+package rbxattr
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math"
+	"testing"
+)
+
+func TestBinaryNumberFloatBits(t *testing.T) {
+	bits32 := []uint32{
+		0x7FC00000, // quiet NaN
+		0x7FC00001, // quiet NaN with payload
+		0x7F800001, // signaling NaN
+		0xFFBFFFFF, // negative signaling NaN with full payload
+		0x00000001, // smallest denormal
+		0x807FFFFF, // largest negative denormal
+		0x80000000, // negative zero
+	}
+	for _, bits := range bits32 {
+		var buf bytes.Buffer
+		bw := newBinaryWriter(&buf)
+		if bw.Number(math.Float32frombits(bits)) {
+			t.Fatalf("float32 %08X: write: %s", bits, bw.Err())
+		}
+		var f float32
+		br := newBinaryReader(&buf)
+		if br.Number(&f) {
+			t.Fatalf("float32 %08X: read: %s", bits, br.Err())
+		}
+		if got := math.Float32bits(f); got != bits {
+			t.Errorf("float32 %08X: got %08X", bits, got)
+		}
+	}
+
+	bits64 := []uint64{
+		0x7FF8000000000000, // quiet NaN
+		0x7FF8DEADBEEF0001, // quiet NaN with payload
+		0x7FF0000000000001, // signaling NaN
+		0xFFF7FFFFFFFFFFFF, // negative signaling NaN with full payload
+		0x0000000000000001, // smallest denormal
+		0x800FFFFFFFFFFFFF, // largest negative denormal
+		0x8000000000000000, // negative zero
+	}
+	for _, bits := range bits64 {
+		var buf bytes.Buffer
+		bw := newBinaryWriter(&buf)
+		if bw.Number(math.Float64frombits(bits)) {
+			t.Fatalf("float64 %016X: write: %s", bits, bw.Err())
+		}
+		var f float64
+		br := newBinaryReader(&buf)
+		if br.Number(&f) {
+			t.Fatalf("float64 %016X: read: %s", bits, br.Err())
+		}
+		if got := math.Float64bits(f); got != bits {
+			t.Errorf("float64 %016X: got %016X", bits, got)
+		}
+	}
+}
+
+func TestModelRoundtripFloatBits(t *testing.T) {
+	// Unsorted keys with NaN payloads and denormals in several value types.
+	nan32 := math.Float32frombits(0x7F800123)
+	den32 := math.Float32frombits(0x00000001)
+	model := Model{Value: ValueDictionary{
+		{Key: "Z", Value: newFloat(nan32)},
+		{Key: "A", Value: newDouble(math.Float64frombits(0x7FF0000000000ABC))},
+		{Key: "M", Value: newDouble(math.Float64frombits(0x8000000000000001))},
+		{Key: "B", Value: &ValueVector3{X: den32, Y: nan32, Z: math.Float32frombits(0x80000000)}},
+		{Key: "Q", Value: &ValueCFrame{
+			Position: ValueVector3{X: nan32},
+			Rotation: [9]float32{den32, 0, 0, 0, 1, 0, 0, 0, nan32},
+		}},
+		{Key: "C", Value: &ValueNumberSequence{{Envelope: nan32, Time: den32, Value: 1}}},
+	}}
+
+	var first bytes.Buffer
+	if _, err := model.WriteTo(&first); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Model
+	if _, err := decoded.ReadFrom(bytes.NewReader(first.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	var second bytes.Buffer
+	if _, err := decoded.WriteTo(&second); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Fatalf("re-encoded bytes differ\n\t%X\n\t%X", first.Bytes(), second.Bytes())
+	}
+}
+
+func TestModelRoundtripCanonical(t *testing.T) {
+	// Forms that Roblox does not produce are rewritten in canonical form.
+	entry := func(typ Type, value ...byte) []byte {
+		return append([]byte{1, 0, 0, 0, 1, 0, 0, 0, 'K', byte(typ)}, value...)
+	}
+	position := make([]byte, 12)
+	explicit := append(append([]byte{}, position...), 0x00)
+	for _, f := range cfIdentity {
+		var b bytes.Buffer
+		newBinaryWriter(&b).Number(f)
+		explicit = append(explicit, b.Bytes()...)
+	}
+	for _, c := range []struct {
+		name     string
+		in, want []byte
+	}{
+		{"Bool", entry(TypeBool, 0x02), entry(TypeBool, 0x01)},
+		{"CFrame explicit", entry(TypeCFrame, explicit...), entry(TypeCFrame, append(position, 0x02)...)},
+		{"CFrame unknown ID", entry(TypeCFrame, append(position, 0x01)...), entry(TypeCFrame, append(position, 0x02)...)},
+	} {
+		var m Model
+		if _, err := m.ReadFrom(bytes.NewReader(c.in)); err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		var out bytes.Buffer
+		if _, err := m.WriteTo(&out); err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if !bytes.Equal(out.Bytes(), c.want) {
+			t.Errorf("%s: expected % X, got % X", c.name, c.want, out.Bytes())
+		}
+	}
+}
+
+func newFloat(f float32) *ValueFloat {
+	v := ValueFloat(f)
+	return &v
+}
+
+func newDouble(f float64) *ValueDouble {
+	v := ValueDouble(f)
+	return &v
+}
+
+// eofReader returns data in chunks of at most Chunk bytes, returning io.EOF
+// together with the final chunk rather than from a separate read.
+type eofReader struct {
+	data  []byte
+	Chunk int
+}
+
+func (r *eofReader) Read(p []byte) (n int, err error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	if len(p) > r.Chunk {
+		p = p[:r.Chunk]
+	}
+	n = copy(p, r.data)
+	r.data = r.data[n:]
+	if len(r.data) == 0 {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func TestBinaryReaderFinalEOF(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	for chunk := 1; chunk <= len(data); chunk++ {
+		p := make([]byte, len(data))
+		if br := newBinaryReader(&eofReader{data: data, Chunk: chunk}); br.Bytes(p) {
+			t.Errorf("Bytes, chunk %d: %s", chunk, br.Err())
+		} else if !bytes.Equal(p, data) {
+			t.Errorf("Bytes, chunk %d: expected %v, got %v", chunk, data, p)
+		}
+		if br := newBinaryReader(&eofReader{data: data, Chunk: chunk}); br.Components(p) {
+			t.Errorf("Components, chunk %d: %s", chunk, br.Err())
+		}
+		if br := newBinaryReader(&eofReader{data: data, Chunk: chunk}); br.Skip(int64(len(data))) {
+			t.Errorf("Skip, chunk %d: %s", chunk, br.Err())
+		}
+		br := newBinaryReader(&eofReader{data: data[:len(data)-1], Chunk: chunk})
+		if !br.Bytes(p) || br.Err() != io.ErrUnexpectedEOF {
+			t.Errorf("Bytes, chunk %d: expected ErrUnexpectedEOF, got %v", chunk, br.Err())
+		}
+	}
+
+	b, err := ioutil.ReadFile("testdata/corpus/all_types.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range []int{1, 3, 7, len(b)} {
+		var model Model
+		n, err := model.ReadFrom(&eofReader{data: b, Chunk: chunk})
+		if err != nil {
+			t.Errorf("ReadFrom, chunk %d: %s", chunk, err)
+		} else if n != int64(len(b)) {
+			t.Errorf("ReadFrom, chunk %d: expected %d bytes read, got %d", chunk, len(b), n)
+		}
+		if err := Validate(&eofReader{data: b, Chunk: chunk}); err != nil {
+			t.Errorf("Validate, chunk %d: %s", chunk, err)
+		}
+		// Truncated input fails exactly as it does with a bytes.Reader.
+		for _, size := range []int{len(b) - 1, len(b) - 2} {
+			_, want := model.ReadFrom(bytes.NewReader(b[:size]))
+			_, err := model.ReadFrom(&eofReader{data: b[:size], Chunk: chunk})
+			if err == nil || want == nil || err.Error() != want.Error() {
+				t.Errorf("ReadFrom truncated to %d, chunk %d: expected error %v, got %v", size, chunk, want, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package rbxattr
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Eval returns the color of the sequence at time t, linearly interpolating
+// between the two surrounding keypoints. Times before the first keypoint or
+// after the last keypoint evaluate to the color of that keypoint. Returns the
+// zero color if the sequence is empty.
+func (v ValueColorSequence) Eval(t float32) ValueColor3 {
+	if len(v) == 0 {
+		return ValueColor3{}
+	}
+	if t <= v[0].Time {
+		return v[0].Value
+	}
+	for i := 1; i < len(v); i++ {
+		a, b := v[i-1], v[i]
+		if t >= b.Time {
+			continue
+		}
+		alpha := (t - a.Time) / (b.Time - a.Time)
+		return ValueColor3{
+			R: a.Value.R + (b.Value.R-a.Value.R)*alpha,
+			G: a.Value.G + (b.Value.G-a.Value.G)*alpha,
+			B: a.Value.B + (b.Value.B-a.Value.B)*alpha,
+		}
+	}
+	return v[len(v)-1].Value
+}
+
+// Image renders the sequence as a gradient image that is width pixels wide and
+// one pixel tall. Each pixel is the result of Eval sampled evenly across the
+// range [0, 1]. An empty sequence renders as a transparent image.
+func (v ValueColorSequence) Image(width int) image.Image {
+	if width < 0 {
+		width = 0
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, 1))
+	if len(v) == 0 {
+		return img
+	}
+	for x := 0; x < width; x++ {
+		var t float32
+		if width > 1 {
+			t = float32(x) / float32(width-1)
+		}
+		img.SetNRGBA(x, 0, v.Eval(t).NRGBA())
+	}
+	return img
+}
+
+// NRGBA returns the color as an opaque color.NRGBA, with each component
+// clamped to the range [0, 1].
+func (v ValueColor3) NRGBA() color.NRGBA {
+	return color.NRGBA{
+		R: colorByte(v.R),
+		G: colorByte(v.G),
+		B: colorByte(v.B),
+		A: 255,
+	}
+}
+
+// colorByte converts a color component to a byte.
+func colorByte(c float32) uint8 {
+	if !(c > 0) {
+		return 0
+	}
+	if c >= 1 {
+		return 255
+	}
+	return uint8(math.Round(float64(c) * 255))
+}
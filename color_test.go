@@ -0,0 +1,40 @@
+package rbxattr_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestColorSequenceImage(t *testing.T) {
+	seq := rbxattr.ValueColorSequence{
+		{Time: 0, Value: rbxattr.ValueColor3{R: 1, G: 0, B: 0}},
+		{Time: 0.5, Value: rbxattr.ValueColor3{R: 0, G: 1, B: 0}},
+		{Time: 1, Value: rbxattr.ValueColor3{R: 0, G: 0, B: 1}},
+	}
+	img := seq.Image(101)
+	if w := img.Bounds().Dx(); w != 101 {
+		t.Fatalf("expected width 101, got %d", w)
+	}
+	for _, c := range []struct {
+		x    int
+		want color.NRGBA
+	}{
+		{0, color.NRGBA{R: 255, A: 255}},
+		{50, color.NRGBA{G: 255, A: 255}},
+		{100, color.NRGBA{B: 255, A: 255}},
+		{25, color.NRGBA{R: 128, G: 128, A: 255}},
+	} {
+		if got := color.NRGBAModel.Convert(img.At(c.x, 0)); got != c.want {
+			t.Errorf("pixel %d: expected %v, got %v", c.x, c.want, got)
+		}
+	}
+
+	img = rbxattr.ValueColorSequence{}.Image(10)
+	for x := 0; x < 10; x++ {
+		if _, _, _, a := img.At(x, 0).RGBA(); a != 0 {
+			t.Fatalf("pixel %d: expected transparent", x)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package rbxattr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// DecodeCompressed decodes a Model from r, which may be compressed with gzip
+// or zlib. The compression is detected from the magic header of the stream.
+// If no header is present, the stream is decoded as a plain model.
+//
+// The first bytes of a plain model with at least 256 entries may happen to form
+// a valid header. If the stream cannot be decoded with the detected
+// compression, it is decoded again as a plain model, and the error of the
+// compressed decoding is returned only if that also fails. To allow this, r is
+// read entirely into memory.
+func DecodeCompressed(r io.Reader) (m Model, err error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return m, err
+	}
+	var newReader func(io.Reader) (io.ReadCloser, error)
+	switch {
+	case isGzipHeader(b):
+		newReader = func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+	case isZlibHeader(b):
+		newReader = zlib.NewReader
+	default:
+		if _, err := m.ReadFrom(bytes.NewReader(b)); err != nil {
+			return Model{}, err
+		}
+		return m, nil
+	}
+	m, cerr := decodeWith(b, newReader)
+	if cerr == nil {
+		return m, nil
+	}
+	if _, err := m.ReadFrom(bytes.NewReader(b)); err != nil {
+		return Model{}, cerr
+	}
+	return m, nil
+}
+
+// decodeWith decodes a Model from b, decompressed by a reader returned by
+// newReader. The stream is read to its end, so that its checksum is verified,
+// and any data following the model is an error.
+func decodeWith(b []byte, newReader func(io.Reader) (io.ReadCloser, error)) (m Model, err error) {
+	zr, err := newReader(bytes.NewReader(b))
+	if err != nil {
+		return m, fmt.Errorf("decompress: %w", err)
+	}
+	defer zr.Close()
+	if _, err := m.ReadFrom(zr); err != nil {
+		return Model{}, err
+	}
+	n, err := io.Copy(ioutil.Discard, zr)
+	if err != nil {
+		return Model{}, fmt.Errorf("decompress: %w", err)
+	}
+	if n > 0 {
+		return Model{}, fmt.Errorf("decompress: %d trailing bytes", n)
+	}
+	return m, nil
+}
+
+// EncodeCompressed encodes m to w, compressed with gzip. The output can be
+// decoded with DecodeCompressed.
+func EncodeCompressed(w io.Writer, m Model) error {
+	zw := gzip.NewWriter(w)
+	if _, err := m.WriteTo(zw); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+	return nil
+}
+
+// CompressedSize returns the number of bytes that the model occupies when
+// encoded by EncodeCompressed. Comparing the result with Size indicates whether
+// compression is worthwhile for the model.
+func (f Model) CompressedSize() (int64, error) {
+	var n countWriter
+	if err := EncodeCompressed(&n, f); err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+// countWriter discards written bytes, counting the number of bytes.
+type countWriter int64
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	*w += countWriter(len(p))
+	return len(p), nil
+}
+
+// isGzipHeader returns whether b begins with the gzip magic number.
+func isGzipHeader(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0x1F && b[1] == 0x8B
+}
+
+// isZlibHeader returns whether b begins with a valid zlib header that uses
+// deflate without a preset dictionary.
+func isZlibHeader(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	cmf, flg := b[0], b[1]
+	return cmf&0x0F == 8 && cmf>>4 <= 7 && flg&0x20 == 0 &&
+		(uint16(cmf)<<8|uint16(flg))%31 == 0
+}
@@ -0,0 +1,135 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestDecodeCompressed(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+	}
+	data := encode(t, dict)
+
+	var gz bytes.Buffer
+	if err := rbxattr.EncodeCompressed(&gz, rbxattr.Model{Value: dict}); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(gz.Bytes(), data) {
+		t.Fatal("expected compressed output to differ from plain encoding")
+	}
+
+	var zl bytes.Buffer
+	zw := zlib.NewWriter(&zl)
+	zw.Write(data)
+	zw.Close()
+
+	for name, input := range map[string][]byte{
+		"gzip":  gz.Bytes(),
+		"zlib":  zl.Bytes(),
+		"plain": data,
+	} {
+		model, err := rbxattr.DecodeCompressed(bytes.NewReader(input))
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		if !model.Value.Equal(dict) {
+			t.Errorf("%s: decoded model does not match", name)
+		}
+	}
+
+	truncated := gz.Bytes()[:gz.Len()/2]
+	if _, err := rbxattr.DecodeCompressed(bytes.NewReader(truncated)); err == nil {
+		t.Error("expected error for truncated gzip stream")
+	}
+}
+
+func TestDecodeCompressedTrailer(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	dict := rbxattr.ValueDictionary{{Key: "Name", Value: &s}}
+	var gz bytes.Buffer
+	if err := rbxattr.EncodeCompressed(&gz, rbxattr.Model{Value: dict}); err != nil {
+		t.Fatal(err)
+	}
+	var zl bytes.Buffer
+	zw := zlib.NewWriter(&zl)
+	zw.Write(encode(t, dict))
+	zw.Close()
+	var extra bytes.Buffer
+	zw = zlib.NewWriter(&extra)
+	zw.Write(append(encode(t, dict), 0))
+	zw.Close()
+
+	// The gzip trailer holds a CRC-32 followed by the length, and the zlib
+	// trailer holds an Adler-32.
+	badCRC := append([]byte{}, gz.Bytes()...)
+	badCRC[len(badCRC)-8] ^= 0xFF
+	badLength := append([]byte{}, gz.Bytes()...)
+	badLength[len(badLength)-1] ^= 0xFF
+	badAdler := append([]byte{}, zl.Bytes()...)
+	badAdler[len(badAdler)-1] ^= 0xFF
+	for name, input := range map[string][]byte{
+		"gzip checksum": badCRC,
+		"gzip length":   badLength,
+		"zlib checksum": badAdler,
+		"trailing data": extra.Bytes(),
+	} {
+		if _, err := rbxattr.DecodeCompressed(bytes.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDecodeCompressedMisdetected(t *testing.T) {
+	// A plain model with 376 (0x178) entries begins with the bytes 78 01, which
+	// form a valid zlib header.
+	b := rbxattr.ValueBool(true)
+	dict := make(rbxattr.ValueDictionary, 376)
+	for i := range dict {
+		dict[i] = rbxattr.Entry{Key: fmt.Sprintf("Key%d", i), Value: &b}
+	}
+	data := encode(t, dict)
+	if data[0] != 0x78 || data[1] != 0x01 {
+		t.Fatalf("expected zlib header, got % X", data[:2])
+	}
+	model, err := rbxattr.DecodeCompressed(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !model.Value.Equal(dict) {
+		t.Error("decoded model does not match")
+	}
+}
+
+func TestModelCompressedSize(t *testing.T) {
+	s := rbxattr.ValueString(strings.Repeat("abcd", 256))
+	var dict rbxattr.ValueDictionary
+	for i := 0; i < 64; i++ {
+		dict = append(dict, rbxattr.Entry{Key: "Attribute", Value: &s})
+	}
+	model := rbxattr.Model{Value: dict}
+	size := model.Size()
+	compressed, err := model.CompressedSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compressed*10 > size {
+		t.Errorf("expected compressed size well below %d, got %d", size, compressed)
+	}
+
+	var buf bytes.Buffer
+	if err := rbxattr.EncodeCompressed(&buf, model); err != nil {
+		t.Fatal(err)
+	}
+	if int64(buf.Len()) != compressed {
+		t.Errorf("expected compressed size %d to match encoding, got %d", buf.Len(), compressed)
+	}
+}
@@ -0,0 +1,81 @@
+package rbxattr
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Coerce converts a numeric value to the numeric type target. Converting a
+// Float to a Double is exact. Converting to a Float rounds to the nearest
+// float32, exactly as a ValueFloatWide is narrowed when encoded, so values
+// beyond the range of float32 become infinite, and the payload of a NaN is
+// retained. A ValueFloatWide coerced to Float becomes a ValueFloat. Otherwise,
+// coercing a value to its own type returns the value unchanged.
+//
+// Returns an error if v or target is not a numeric type. Roblox does not
+// allow Int attributes, so the Int type is not implemented, and cannot be
+// coerced to or from.
+func Coerce(v Value, target Type) (Value, error) {
+	var f float64
+	switch v := v.(type) {
+	case *ValueFloat:
+		f = widenFloat(float32(*v))
+	case *ValueFloatWide:
+		f = float64(*v)
+	case *ValueDouble:
+		f = float64(*v)
+	default:
+		if v == nil {
+			return nil, fmt.Errorf("cannot coerce nil value to %s", target)
+		}
+		return nil, fmt.Errorf("cannot coerce %s to %s", v.Type(), target)
+	}
+	if w, ok := v.(*ValueFloatWide); ok && target == TypeFloat {
+		c := ValueFloat(narrowFloat(float64(*w)))
+		return &c, nil
+	}
+	if v.Type() == target {
+		return v, nil
+	}
+	switch target {
+	case TypeFloat:
+		c := ValueFloat(narrowFloat(f))
+		return &c, nil
+	case TypeDouble:
+		c := ValueDouble(f)
+		return &c, nil
+	}
+	return nil, fmt.Errorf("cannot coerce %s to %s", v.Type(), target)
+}
+
+// Rat returns the exact rational value of v, or nil if v is NaN or infinite.
+func (v ValueFloat) Rat() *big.Rat {
+	return new(big.Rat).SetFloat64(float64(v))
+}
+
+// Rat returns the exact rational value of v, or nil if v is NaN or infinite.
+func (v ValueDouble) Rat() *big.Rat {
+	return new(big.Rat).SetFloat64(float64(v))
+}
+
+// DoubleFromRat returns the Double nearest to r, and whether it represents r
+// exactly.
+func DoubleFromRat(r *big.Rat) (v ValueDouble, exact bool) {
+	f, exact := r.Float64()
+	return ValueDouble(f), exact
+}
+
+// ToDouble returns a deep copy of f in which each Float value is widened to a
+// Double, which is exact. Float components of other types, such as Vector3 and
+// Color3, are not widened, because the encoding of those types cannot hold
+// double-precision components.
+func (f Model) ToDouble() Model {
+	d := f.Value.Clone()
+	for i, entry := range d {
+		switch v := entry.Value.(type) {
+		case *ValueFloat, *ValueFloatWide:
+			d[i].Value, _ = Coerce(v, TypeDouble)
+		}
+	}
+	return Model{Value: d}
+}
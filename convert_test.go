@@ -0,0 +1,142 @@
+package rbxattr_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestCoerce(t *testing.T) {
+	f := rbxattr.ValueFloat(0.1)
+	v, err := rbxattr.Coerce(&f, rbxattr.TypeDouble)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := *v.(*rbxattr.ValueDouble); float64(d) != float64(float32(0.1)) {
+		t.Fatalf("expected exact widening, got %v", d)
+	}
+
+	d := rbxattr.ValueDouble(1e300)
+	v, err = rbxattr.Coerce(&d, rbxattr.TypeFloat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := *v.(*rbxattr.ValueFloat); !math.IsInf(float64(f), 1) {
+		t.Fatalf("expected overflow to infinity, got %v", f)
+	}
+
+	d = rbxattr.ValueDouble(0.1)
+	v, err = rbxattr.Coerce(&d, rbxattr.TypeFloat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := *v.(*rbxattr.ValueFloat); float32(f) != float32(0.1) {
+		t.Fatalf("expected rounding to nearest, got %v", f)
+	}
+
+	if v, err := rbxattr.Coerce(&d, rbxattr.TypeDouble); err != nil || v != &d {
+		t.Fatalf("expected identity coercion, got %v, %v", v, err)
+	}
+
+	s := rbxattr.ValueString("1")
+	for _, c := range []struct {
+		v      rbxattr.Value
+		target rbxattr.Type
+	}{
+		{&s, rbxattr.TypeDouble},
+		{&d, rbxattr.TypeString},
+		{&d, 0x04},
+		{nil, rbxattr.TypeDouble},
+	} {
+		if _, err := rbxattr.Coerce(c.v, c.target); err == nil {
+			t.Errorf("%v to %s: expected error", c.v, c.target)
+		}
+	}
+}
+
+func TestCoerceNaN(t *testing.T) {
+	// A NaN with a payload is narrowed as it is when encoded.
+	const bits = 0x7FC12345
+	wide := rbxattr.ValueFloatWide(math.Float64frombits(0x7FF8_2468_A000_0000))
+	d := rbxattr.ValueDouble(wide)
+	for _, v := range []rbxattr.Value{&wide, &d} {
+		c, err := rbxattr.Coerce(v, rbxattr.TypeFloat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, ok := c.(*rbxattr.ValueFloat)
+		if !ok {
+			t.Fatalf("%T: expected Float, got %T", v, c)
+		}
+		if got := math.Float32bits(float32(*f)); got != bits {
+			t.Errorf("%T: expected bits %08X, got %08X", v, uint32(bits), got)
+		}
+	}
+	f := rbxattr.ValueFloat(math.Float32frombits(bits))
+	c, err := rbxattr.Coerce(&f, rbxattr.TypeDouble)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := math.Float64bits(float64(*c.(*rbxattr.ValueDouble))); got != 0x7FF8_2468_A000_0000 {
+		t.Errorf("expected payload to widen, got %016X", got)
+	}
+}
+
+func TestRat(t *testing.T) {
+	d := rbxattr.ValueDouble(0.1)
+	r := d.Rat()
+	if want := "3602879701896397/36028797018963968"; r.String() != want {
+		t.Errorf("expected %s, got %s", want, r)
+	}
+	if v, exact := rbxattr.DoubleFromRat(r); v != d || !exact {
+		t.Errorf("expected exact %v, got %v (exact %t)", d, v, exact)
+	}
+
+	f := rbxattr.ValueFloat(0.1)
+	if want := "13421773/134217728"; f.Rat().String() != want {
+		t.Errorf("expected %s, got %s", want, f.Rat())
+	}
+
+	third := big.NewRat(1, 3)
+	if v, exact := rbxattr.DoubleFromRat(third); exact || v != rbxattr.ValueDouble(1.0/3) {
+		t.Errorf("expected inexact %v, got %v (exact %t)", 1.0/3, v, exact)
+	}
+	if r := rbxattr.ValueDouble(math.NaN()).Rat(); r != nil {
+		t.Errorf("expected nil for NaN, got %s", r)
+	}
+}
+
+func TestModelToDouble(t *testing.T) {
+	f := rbxattr.ValueFloat(0.1)
+	w := rbxattr.ValueFloatWide(float32(1) / 3)
+	s := rbxattr.ValueString("foo")
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Scale", Value: &f},
+		{Key: "Name", Value: &s},
+		{Key: "Ratio", Value: &w},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 0.1}},
+	}}
+	got := model.ToDouble()
+	for key, want := range map[string]float64{
+		"Scale": float64(float32(0.1)),
+		"Ratio": float64(float32(1) / 3),
+	} {
+		v, _ := got.Value.Get(key)
+		d, ok := v.(*rbxattr.ValueDouble)
+		if !ok {
+			t.Errorf("%s: expected Double, got %T", key, v)
+			continue
+		}
+		if float64(*d) != want {
+			t.Errorf("%s: expected %v, got %v", key, want, float64(*d))
+		}
+	}
+	if v, _ := got.Value.Get("Position"); v.Type() != rbxattr.TypeVector3 {
+		t.Errorf("Position: expected Vector3, got %s", v.Type())
+	}
+	if v, _ := model.Value.Get("Scale"); v != &f {
+		t.Error("original model was modified")
+	}
+}
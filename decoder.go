@@ -0,0 +1,645 @@
+package rbxattr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Decoder decodes a Model from an input stream. The exported fields of a
+// Decoder configure how the stream is interpreted. The zero value of each
+// option matches the behavior of Model.ReadFrom.
+//
+// Decoder does not manage time limits itself. To bound the duration of a
+// decode, set a deadline on the underlying connection, or use DecodeTimeout. A
+// failure to read, including an expired deadline, is returned as a
+// *DecodeError that reports the offset at which it occurred.
+type Decoder struct {
+	r io.Reader
+
+	// Repair enables best-effort recovery of dictionaries that declare more
+	// entries than are present. If the stream ends cleanly between two
+	// entries before the declared number of entries has been read, then
+	// decoding stops successfully with the entries found so far. Repaired
+	// reports the outcome.
+	Repair bool
+
+	// ScanUnknown causes decoding to continue scanning after a value of an
+	// unknown type is encountered, so that the returned UnknownTypeError
+	// includes every unknown type in the dictionary rather than only the
+	// first. Because the size of an unknown value cannot be determined, the
+	// scan resynchronizes by searching for the next plausible entry, and is
+	// therefore a best-effort analysis. Decoding still fails.
+	ScanUnknown bool
+
+	// StringsAsBytes causes String values to be decoded as ValueBytes rather
+	// than ValueString, avoiding a copy of each string. See ValueBytes for the
+	// aliasing that occurs when the input stream is a *bytes.Buffer.
+	StringsAsBytes bool
+
+	// WideFloats causes Float values to be decoded as ValueFloatWide rather
+	// than ValueFloat. Such values are narrowed back to float32 when encoded.
+	WideFloats bool
+
+	// VerifyCRC causes Decode to read a CRC-32 checksum (IEEE) following the
+	// dictionary, as written by an Encoder with the AppendCRC option, and to
+	// fail with ErrChecksum if it does not match the decoded bytes. It does
+	// not apply to streaming with DecodeEntry.
+	VerifyCRC bool
+
+	// OnEntry, if not nil, is called after each entry is decoded, with the
+	// key and type of the entry, and the number of bytes of the encoded entry,
+	// including its key and type.
+	OnEntry func(key string, t Type, size int64)
+
+	// InternStrings causes keys and String values to be deduplicated through
+	// a pool of strings kept by the decoder, so that equal strings decoded by
+	// the decoder share the same memory. This reduces memory when decoding
+	// many similar models, such as with repeated calls to Decode. Interned
+	// strings are shared, and must be treated as immutable. The pool retains
+	// each distinct string for the lifetime of the decoder. It does not apply
+	// to values decoded with StringsAsBytes.
+	InternStrings bool
+
+	// StrictFloats causes decoding to fail if a float component of a value is
+	// a signaling NaN, which is valid, but is not produced by Roblox, and
+	// therefore indicates corruption. The error reports the entry and the
+	// path to the component within the value.
+	StrictFloats bool
+
+	// RejectDenormals is like StrictFloats, but rejects float components that
+	// are denormal. A Float decoded with WideFloats is checked as a float32.
+	RejectDenormals bool
+
+	declared int
+	found    int
+	pool     *stringPool
+
+	// State of streaming decoding.
+	checkpoint int64
+	remaining  int
+	key        []byte
+}
+
+// NewDecoder returns a Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Repaired returns the number of entries declared by the most recently
+// decoded dictionary, and the number of entries that were actually found. The
+// two differ only when Repair is enabled and a repair occurred.
+func (d *Decoder) Repaired() (declared, found int) {
+	return d.declared, d.found
+}
+
+// newValue returns a new Value of the given Type according to the options of
+// the decoder.
+func (d *Decoder) newValue(typ Type) Value {
+	if typ == TypeString && d.StringsAsBytes {
+		return new(ValueBytes)
+	}
+	if typ == TypeFloat && d.WideFloats {
+		return new(ValueFloatWide)
+	}
+	return NewValue(typ)
+}
+
+// checkFloats returns an error describing the first float component of v that
+// is rejected by the StrictFloats or RejectDenormals options.
+func (d *Decoder) checkFloats(v Value) (err error) {
+	if !d.StrictFloats && !d.RejectDenormals {
+		return nil
+	}
+	walkLeaves(v.Type().String(), reflect.ValueOf(v), func(path string, leaf reflect.Value) {
+		if err != nil {
+			return
+		}
+		// Bits are taken from the interface, as converting a float32 to a
+		// float64 may quiet a signaling NaN.
+		var f32 float32
+		switch f := leaf.Interface().(type) {
+		case float32:
+			f32 = f
+		case ValueFloat:
+			f32 = float32(f)
+		case ValueFloatWide:
+			f32 = narrowFloat(float64(f))
+		case float64, ValueDouble:
+			b := math.Float64bits(leaf.Float())
+			exp, frac := b>>52&0x7FF, b&(1<<52-1)
+			switch {
+			case d.StrictFloats && exp == 0x7FF && frac != 0 && frac&(1<<51) == 0:
+				err = fmt.Errorf("%s: signaling NaN %016X", path, b)
+			case d.RejectDenormals && exp == 0 && frac != 0:
+				err = fmt.Errorf("%s: denormal %016X", path, b)
+			}
+			return
+		default:
+			return
+		}
+		b := math.Float32bits(f32)
+		exp, frac := b>>23&0xFF, b&(1<<23-1)
+		switch {
+		case d.StrictFloats && exp == 0xFF && frac != 0 && frac&(1<<22) == 0:
+			err = fmt.Errorf("%s: signaling NaN %08X", path, b)
+		case d.RejectDenormals && exp == 0 && frac != 0:
+			err = fmt.Errorf("%s: denormal %08X", path, b)
+		}
+	})
+	return err
+}
+
+// strings returns the pool used to intern strings, or nil if strings are not
+// interned.
+func (d *Decoder) strings() *stringPool {
+	if !d.InternStrings {
+		return nil
+	}
+	if d.pool == nil {
+		d.pool = &stringPool{pool: map[string]string{}}
+	}
+	return d.pool
+}
+
+// DecodeError is returned by a Decoder when decoding fails.
+type DecodeError struct {
+	// Offset is the number of bytes read from the input stream before the
+	// failure occurred.
+	Offset int64
+	Err    error
+}
+
+func (err *DecodeError) Error() string {
+	return fmt.Sprintf("format: offset %d: %s", err.Offset, err.Err)
+}
+
+func (err *DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// Timeout returns whether the error was caused by a timeout, such as an
+// expired deadline of a network connection.
+func (err *DecodeError) Timeout() bool {
+	var t interface{ Timeout() bool }
+	return errors.As(err.Err, &t) && t.Timeout()
+}
+
+// Decode decodes a Model from the input stream.
+func (d *Decoder) Decode() (m Model, err error) {
+	r := d.r
+	var crc hash.Hash32
+	if d.VerifyCRC {
+		crc = crc32.NewIEEE()
+		r = io.TeeReader(d.r, crc)
+	}
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return m, &DecodeError{Offset: br.N(), Err: fmt.Errorf("Dictionary length: %w", br.Err())}
+	}
+	d.declared = int(length)
+	d.found = 0
+	dict := make(ValueDictionary, 0, preallocLen(length))
+	offset := br.N()
+	for i := 0; i < int(length); i++ {
+		var entry Entry
+		n, err := entry.readFrom(r, i, d.newValue, d.strings())
+		offset += n
+		if err != nil {
+			if d.Repair && n == 0 && errors.Is(err, io.EOF) {
+				break
+			}
+			var unknown *UnknownTypeError
+			if d.ScanUnknown && errors.As(err, &unknown) {
+				if rest, rerr := ioutil.ReadAll(r); rerr == nil {
+					unknown.Types = scanUnknown(rest, int(length)-i-1, unknown.Types)
+				}
+				err = fmt.Errorf("Dictionary[%d](%q) value: %w", i, entry.Key, unknown)
+			}
+			return m, &DecodeError{Offset: offset, Err: err}
+		}
+		if err := d.checkFloats(entry.Value); err != nil {
+			return m, &DecodeError{Offset: offset, Err: fmt.Errorf("Dictionary[%d](%q) value: %w", i, entry.Key, err)}
+		}
+		if d.OnEntry != nil {
+			d.OnEntry(entry.Key, entry.Value.Type(), n)
+		}
+		dict = append(dict, entry)
+		d.found++
+	}
+	if crc != nil {
+		br := newBinaryReader(d.r)
+		var sum uint32
+		if br.Number(&sum) {
+			return m, &DecodeError{Offset: offset + br.N(), Err: fmt.Errorf("CRC: %w", br.Err())}
+		}
+		if sum != crc.Sum32() {
+			return m, &DecodeError{Offset: offset, Err: fmt.Errorf("CRC: %w: stored %08X, computed %08X", ErrChecksum, sum, crc.Sum32())}
+		}
+	}
+	m.Value = dict
+	return m, nil
+}
+
+// ErrChecksum is returned when a checksum does not match the decoded bytes.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// BeginDictionary begins streaming a dictionary by reading its length prefix,
+// and returns the number of entries it declares. Each entry can then be
+// decoded with DecodeEntry. The Repair option applies to streamed entries,
+// while ScanUnknown does not.
+func (d *Decoder) BeginDictionary() (count int, err error) {
+	br := newBinaryReader(d.r)
+	var length uint32
+	if br.Number(&length) {
+		return 0, &DecodeError{Offset: br.N(), Err: fmt.Errorf("Dictionary length: %w", br.Err())}
+	}
+	d.declared = int(length)
+	d.found = 0
+	d.checkpoint = br.N()
+	d.remaining = int(length)
+	return int(length), nil
+}
+
+// DecodeEntry decodes the next entry of a dictionary begun with
+// BeginDictionary. Returns io.EOF when no entries remain.
+//
+// If an error occurs, the decoder remains at the last checkpoint, and calling
+// DecodeEntry again retries the failed entry. Before retrying, the underlying
+// reader must be positioned at the offset returned by Checkpoint. That is, it
+// must not have consumed any bytes past the checkpoint, or it must be returned
+// to the checkpoint, such as by seeking.
+func (d *Decoder) DecodeEntry() (entry Entry, err error) {
+	if d.remaining <= 0 {
+		return entry, io.EOF
+	}
+	n, err := entry.readFrom(d.r, d.found, d.newValue, d.strings())
+	if err != nil {
+		if d.Repair && n == 0 && errors.Is(err, io.EOF) {
+			d.remaining = 0
+			return Entry{}, io.EOF
+		}
+		return Entry{}, &DecodeError{Offset: d.checkpoint + n, Err: err}
+	}
+	if err := d.checkFloats(entry.Value); err != nil {
+		return Entry{}, &DecodeError{Offset: d.checkpoint + n, Err: fmt.Errorf("Dictionary[%d](%q) value: %w", d.found, entry.Key, err)}
+	}
+	if d.OnEntry != nil {
+		d.OnEntry(entry.Key, entry.Value.Type(), n)
+	}
+	d.checkpoint += n
+	d.remaining--
+	d.found++
+	return entry, nil
+}
+
+// DecodeEntryBytes is like DecodeEntry, but returns the key of the entry as a
+// byte slice, avoiding the allocation of a string for each key. This allows
+// keys to be compared against known names cheaply when most entries are
+// ignored.
+//
+// The returned key aliases a buffer owned by the decoder, and is valid only
+// until the next call to a method of the decoder. It must not be modified. To
+// retain the key, copy it, such as by converting it to a string.
+func (d *Decoder) DecodeEntryBytes() (key []byte, value Value, err error) {
+	if d.remaining <= 0 {
+		return nil, nil, io.EOF
+	}
+	br := newBinaryReader(d.r)
+	i := d.found
+	var length uint32
+	if br.Number(&length) {
+		if d.Repair && br.N() == 0 && errors.Is(br.Err(), io.EOF) {
+			d.remaining = 0
+			return nil, nil, io.EOF
+		}
+		return nil, nil, &DecodeError{Offset: d.checkpoint + br.N(), Err: fmt.Errorf("Dictionary[%d] key: %w", i, br.Err())}
+	}
+	if br.Buffer(&d.key, length) {
+		return nil, nil, &DecodeError{Offset: d.checkpoint + br.N(), Err: fmt.Errorf("Dictionary[%d] key: %w", i, br.Err())}
+	}
+	key = d.key
+	var typ byte
+	if br.Number(&typ) {
+		return nil, nil, &DecodeError{Offset: d.checkpoint + br.N(), Err: fmt.Errorf("Dictionary[%d](%q) type: %w", i, key, br.Err())}
+	}
+	value = d.newValue(Type(typ))
+	if value == nil {
+		err := fmt.Errorf("Dictionary[%d](%q) value: %w", i, key, &UnknownTypeError{Types: []Type{Type(typ)}})
+		return nil, nil, &DecodeError{Offset: d.checkpoint + br.N(), Err: err}
+	}
+	if br.Add(d.strings().readValue(d.r, value)) {
+		return nil, nil, &DecodeError{Offset: d.checkpoint + br.N(), Err: fmt.Errorf("Dictionary[%d](%q) value: %w", i, key, br.Err())}
+	}
+	if err := d.checkFloats(value); err != nil {
+		return nil, nil, &DecodeError{Offset: d.checkpoint + br.N(), Err: fmt.Errorf("Dictionary[%d](%q) value: %w", i, key, err)}
+	}
+	if d.OnEntry != nil {
+		d.OnEntry(string(key), value.Type(), br.N())
+	}
+	d.checkpoint += br.N()
+	d.remaining--
+	d.found++
+	return key, value, nil
+}
+
+// Checkpoint returns the offset in the input stream following the last entry
+// successfully decoded by DecodeEntry or DecodeEntryBytes, or following the
+// length prefix if no entries have been decoded. Streaming resumes from this
+// offset after an error.
+func (d *Decoder) Checkpoint() int64 {
+	return d.checkpoint
+}
+
+// scanUnknown scans the remaining entries of a dictionary following a value of
+// an unknown type, appending to types each distinct unknown type that is
+// found.
+func scanUnknown(rest []byte, remaining int, types []Type) []Type {
+	for lost := true; remaining > 0 && len(rest) > 0; remaining-- {
+		offset := 0
+		if lost {
+			if offset = findEntry(rest); offset < 0 {
+				break
+			}
+		}
+		r := bytes.NewReader(rest[offset:])
+		br := newBinaryReader(r)
+		var key string
+		var typ byte
+		if br.String(&key) || br.Number(&typ) {
+			break
+		}
+		value := NewValue(Type(typ))
+		if value == nil {
+			types = appendType(types, Type(typ))
+			lost = true
+		} else if br.Add(value.ReadFrom(r)) {
+			break
+		} else {
+			lost = false
+		}
+		rest = rest[offset+int(br.N()):]
+	}
+	return types
+}
+
+// UnsupportedTypes scans an encoded Model from r, and returns each distinct
+// type, in order of appearance, that is not supported by NewValue. Values are
+// skipped without being decoded. Because the size of an unsupported value
+// cannot be determined, scanning beyond the first such value resynchronizes in
+// the same way as the ScanUnknown option of Decoder, and is therefore
+// best-effort. Returns an error if the stream is malformed before the first
+// unsupported value.
+func UnsupportedTypes(r io.Reader) ([]Type, error) {
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return nil, fmt.Errorf("format: Dictionary length: %w", br.Err())
+	}
+	for i := 0; i < int(length); i++ {
+		var keyLength uint32
+		if br.Number(&keyLength) || br.Skip(int64(keyLength)) {
+			return nil, fmt.Errorf("format: Dictionary[%d] key: %w", i, br.Err())
+		}
+		var typ byte
+		if br.Number(&typ) {
+			return nil, fmt.Errorf("format: Dictionary[%d] type: %w", i, br.Err())
+		}
+		n, err := skipValue(r, Type(typ))
+		var unknown *UnknownTypeError
+		if errors.As(err, &unknown) {
+			types := unknown.Types
+			if rest, err := ioutil.ReadAll(r); err == nil {
+				types = scanUnknown(rest, int(length)-i-1, types)
+			}
+			return types, nil
+		}
+		if br.Add(n, err) {
+			return nil, fmt.Errorf("format: Dictionary[%d] value: %w", i, br.Err())
+		}
+	}
+	return nil, nil
+}
+
+// ListKeys reads an encoded Model from r, and returns the key of each entry,
+// in order, including duplicate keys. Values are skipped without being
+// decoded, which is faster and uses less memory than decoding the model.
+func ListKeys(r io.Reader) ([]string, error) {
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return nil, fmt.Errorf("format: Dictionary length: %w", br.Err())
+	}
+	keys := make([]string, 0, preallocLen(length))
+	for i := 0; i < int(length); i++ {
+		var key string
+		if br.String(&key) {
+			return nil, fmt.Errorf("format: Dictionary[%d] key: %w", i, br.Err())
+		}
+		var typ byte
+		if br.Number(&typ) {
+			return nil, fmt.Errorf("format: Dictionary[%d](%q) type: %w", i, key, br.Err())
+		}
+		if br.Add(skipValue(r, Type(typ))) {
+			return nil, fmt.Errorf("format: Dictionary[%d](%q) value: %w", i, key, br.Err())
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// OffsetOf returns the offset within b of the value of the first entry with
+// the given key, which is the offset following the type of the entry. Entries
+// are skipped without being decoded. Returns false if no entry has the key, or
+// if b is malformed before the entry is found.
+func OffsetOf(b []byte, key string) (valueOffset int64, ok bool) {
+	r := bytes.NewReader(b)
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return 0, false
+	}
+	for i := 0; i < int(length); i++ {
+		var k string
+		var typ byte
+		if br.String(&k) || br.Number(&typ) {
+			return 0, false
+		}
+		if k == key {
+			return br.N(), true
+		}
+		if br.Add(skipValue(r, Type(typ))) {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// findEntry returns the offset of the first plausible entry in b, or -1 if
+// there is none. An entry is plausible if its key is a valid attribute name.
+func findEntry(b []byte) int {
+	for i := 0; i+4 < len(b); i++ {
+		length := binary.LittleEndian.Uint32(b[i:])
+		if length == 0 || length > 100 || uint64(i)+4+uint64(length) >= uint64(len(b)) {
+			continue
+		}
+		if validKey(b[i+4 : i+4+int(length)]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// validKey returns whether key contains only alphanumeric bytes and
+// underscores.
+func validKey(key []byte) bool {
+	for _, c := range key {
+		switch {
+		case '0' <= c && c <= '9', 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// appendType appends typ to types if it is not already present.
+func appendType(types []Type, typ Type) []Type {
+	for _, t := range types {
+		if t == typ {
+			return types
+		}
+	}
+	return append(types, typ)
+}
+
+// TimeoutError is returned by DecodeTimeout when decoding does not complete
+// within the time limit.
+type TimeoutError struct {
+	// Duration is the time limit that was exceeded.
+	Duration time.Duration
+}
+
+func (err *TimeoutError) Error() string {
+	return fmt.Sprintf("format: decode timed out after %s", err.Duration)
+}
+
+// Timeout returns true, indicating that the error is a timeout.
+func (err *TimeoutError) Timeout() bool {
+	return true
+}
+
+// DecodeTimeout decodes a Model from r, returning a *TimeoutError if decoding
+// does not complete within d. Decoding runs in a separate goroutine, which
+// continues after a timeout until the read from r returns, and may consume
+// further bytes from r. The result of the abandoned decode is discarded.
+// Where possible, prefer setting a deadline on the underlying connection,
+// which stops the read itself.
+func DecodeTimeout(r io.Reader, d time.Duration) (m Model, err error) {
+	type result struct {
+		m   Model
+		err error
+	}
+	// Buffered so that an abandoned decode does not block.
+	done := make(chan result, 1)
+	go func() {
+		var m Model
+		_, err := m.ReadFrom(r)
+		done <- result{m: m, err: err}
+	}()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return res.m, res.err
+	case <-timer.C:
+		return Model{}, &TimeoutError{Duration: d}
+	}
+}
+
+// SeekError is returned by DecodeSeek when decoding fails.
+type SeekError struct {
+	// Start is the offset of the stream at which decoding started.
+	Start int64
+	Err   error
+}
+
+func (err *SeekError) Error() string {
+	return fmt.Sprintf("decode from offset %d: %s", err.Start, err.Err)
+}
+
+func (err *SeekError) Unwrap() error {
+	return err.Err
+}
+
+// DecodeSeek decodes a Model from the current offset of rs. If decoding fails,
+// rs is returned to the starting offset, so that decoding can be retried, for
+// example with different Decoder options. The returned error is a *SeekError
+// that records the starting offset.
+func DecodeSeek(rs io.ReadSeeker) (m Model, err error) {
+	start, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return m, err
+	}
+	if _, err = m.ReadFrom(rs); err != nil {
+		if _, serr := rs.Seek(start, io.SeekStart); serr != nil {
+			err = fmt.Errorf("%w; seek to start: %s", err, serr)
+		}
+		return Model{}, &SeekError{Start: start, Err: err}
+	}
+	return m, nil
+}
+
+// stringPool interns strings as they are read. A nil *stringPool reads strings
+// without interning.
+type stringPool struct {
+	pool map[string]string
+	buf  []byte
+}
+
+// String reads a string from br like binaryReader.String, returning the
+// interned copy of the string.
+func (p *stringPool) String(br *binaryReader, data *string) (failed bool) {
+	if p == nil {
+		return br.String(data)
+	}
+	var length uint32
+	if br.Number(&length) {
+		return true
+	}
+	if br.Buffer(&p.buf, length) {
+		return true
+	}
+	b := p.buf
+	s, ok := p.pool[string(b)]
+	if !ok {
+		s = string(b)
+		p.pool[s] = s
+	}
+	*data = s
+	return false
+}
+
+// readValue reads v from r, interning the content of v if it is a String.
+func (p *stringPool) readValue(r io.Reader, v Value) (n int64, err error) {
+	s, ok := v.(*ValueString)
+	if p == nil || !ok {
+		return v.ReadFrom(r)
+	}
+	br := newBinaryReader(r)
+	var a string
+	if p.String(br, &a) {
+		return br.N(), fmt.Errorf("String: %w", br.Err())
+	}
+	*s = ValueString(a)
+	return br.End()
+}
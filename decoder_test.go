@@ -0,0 +1,685 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+// encode returns the encoded bytes of a dictionary.
+func encode(t testing.TB, d rbxattr.ValueDictionary) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecoderRepair(t *testing.T) {
+	a := rbxattr.ValueString("foo")
+	b := rbxattr.ValueBool(true)
+	data := encode(t, rbxattr.ValueDictionary{
+		{Key: "A", Value: &a},
+		{Key: "B", Value: &b},
+	})
+	binary.LittleEndian.PutUint32(data, 3)
+
+	if _, err := rbxattr.NewDecoder(bytes.NewReader(data)).Decode(); err == nil {
+		t.Fatal("expected error from strict decode")
+	}
+
+	dec := rbxattr.NewDecoder(bytes.NewReader(data))
+	dec.Repair = true
+	model, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, "repaired", model.Value, "A", "B")
+	if declared, found := dec.Repaired(); declared != 3 || found != 2 {
+		t.Fatalf("expected 3 declared and 2 found, got %d and %d", declared, found)
+	}
+
+	// Truncation within an entry is not repaired.
+	dec = rbxattr.NewDecoder(bytes.NewReader(data[:len(data)-1]))
+	dec.Repair = true
+	if _, err := dec.Decode(); err == nil {
+		t.Fatal("expected error from truncated entry")
+	}
+
+	// A huge declared length is repaired without preallocating every entry.
+	binary.LittleEndian.PutUint32(data, math.MaxUint32)
+	dec = rbxattr.NewDecoder(bytes.NewReader(data))
+	dec.Repair = true
+	if model, err = dec.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, "huge", model.Value, "A", "B")
+	if declared, found := dec.Repaired(); declared != math.MaxUint32 || found != 2 {
+		t.Fatalf("expected %d declared and 2 found, got %d and %d", uint32(math.MaxUint32), declared, found)
+	}
+}
+
+func TestDecoderScanUnknown(t *testing.T) {
+	var buf bytes.Buffer
+	entry := func(key string, typ byte, value []byte) {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(key)))
+		buf.WriteString(key)
+		buf.WriteByte(typ)
+		buf.Write(value)
+	}
+	binary.Write(&buf, binary.LittleEndian, uint32(6))
+	entry("Name", byte(rbxattr.TypeString), []byte{3, 0, 0, 0, 'f', 'o', 'o'})
+	entry("Faces", 0x0C, []byte{0xFF, 0xFF})
+	entry("Enabled", byte(rbxattr.TypeBool), []byte{1})
+	entry("Region", 0x1F, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	entry("Other", 0x0C, nil)
+	entry("Scale", byte(rbxattr.TypeFloat), []byte{0, 0, 0x80, 0x3F})
+	data := buf.Bytes()
+
+	var unknown *rbxattr.UnknownTypeError
+	_, err := rbxattr.NewDecoder(bytes.NewReader(data)).Decode()
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected UnknownTypeError, got %v", err)
+	}
+	if len(unknown.Types) != 1 || unknown.Types[0] != 0x0C {
+		t.Fatalf("expected only first unknown type, got %v", unknown.Types)
+	}
+
+	dec := rbxattr.NewDecoder(bytes.NewReader(data))
+	dec.ScanUnknown = true
+	_, err = dec.Decode()
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected UnknownTypeError, got %v", err)
+	}
+	if len(unknown.Types) != 2 || unknown.Types[0] != 0x0C || unknown.Types[1] != 0x1F {
+		t.Fatalf("expected types 0x0C and 0x1F, got %v", unknown.Types)
+	}
+	const msg = `format: offset 30: Dictionary[1]("Faces") value: unknown data types 0x0C, 0x1F`
+	if err.Error() != msg {
+		t.Fatalf("unexpected error message %q", err)
+	}
+}
+
+func TestDecoderStringsAsBytes(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	f := rbxattr.ValueFloat(1)
+	data := encode(t, rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Scale", Value: &f},
+	})
+
+	dec := rbxattr.NewDecoder(bytes.NewBuffer(data))
+	dec.StringsAsBytes = true
+	model, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok := model.Value[0].Value.(*rbxattr.ValueBytes)
+	if !ok {
+		t.Fatalf("expected *ValueBytes, got %T", model.Value[0].Value)
+	}
+	if string(*b) != "foobar" {
+		t.Fatalf("expected foobar, got %q", *b)
+	}
+	// Content aliases the buffer.
+	data[len("\x02\x00\x00\x00\x04\x00\x00\x00Name\x02\x06\x00\x00\x00")] = 'g'
+	if string(*b) != "goobar" {
+		t.Fatalf("expected content to alias buffer, got %q", *b)
+	}
+	if got := encode(t, model.Value); !bytes.Equal(got, data) {
+		t.Fatalf("re-encoded bytes do not match\n\t%X\n\t%X", data, got)
+	}
+
+	if _, err := rbxattr.NewDecoder(bytes.NewBuffer(data[:len(data)-6])).Decode(); err == nil {
+		t.Fatal("expected error for truncated string")
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// timeoutReader reads from R until Limit bytes have been read, then fails
+// with a timeout.
+type timeoutReader struct {
+	R     io.Reader
+	Limit int64
+}
+
+func (r *timeoutReader) Read(p []byte) (n int, err error) {
+	if r.Limit <= 0 {
+		return 0, timeoutError{}
+	}
+	if int64(len(p)) > r.Limit {
+		p = p[:r.Limit]
+	}
+	n, err = r.R.Read(p)
+	r.Limit -= int64(n)
+	return n, err
+}
+
+func TestDecoderTimeout(t *testing.T) {
+	data := encode(t, rbxattr.ValueDictionary{
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+	})
+	// Fail partway through the Scale of Size.X.
+	const limit = 4 + (4 + 8 + 1 + 12) + (4 + 4 + 1) + 2
+	r := &timeoutReader{R: bytes.NewReader(data), Limit: limit}
+	_, err := rbxattr.NewDecoder(r).Decode()
+
+	var derr *rbxattr.DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if derr.Offset != limit {
+		t.Errorf("expected offset %d, got %d", limit, derr.Offset)
+	}
+	if !derr.Timeout() {
+		t.Error("expected timeout")
+	}
+	if !errors.Is(err, timeoutError{}) {
+		t.Error("expected error to wrap timeout")
+	}
+	const msg = `format: offset 40: Dictionary[1]("Size") value: UDim2.X: UDim.Scale: i/o timeout`
+	if err.Error() != msg {
+		t.Errorf("unexpected error message %q", err)
+	}
+}
+
+func TestDecodeSeek(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	data := encode(t, rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+	})
+	r := bytes.NewReader(append([]byte{0xAA, 0xBB, 0xCC}, data...))
+	r.Seek(3, io.SeekStart)
+	model, err := rbxattr.DecodeSeek(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, "decoded", model.Value, "Name")
+
+	// Over-declare the length so that strict decoding fails.
+	binary.LittleEndian.PutUint32(data, 2)
+	r = bytes.NewReader(append([]byte{0xAA, 0xBB, 0xCC}, data...))
+	r.Seek(3, io.SeekStart)
+	_, err = rbxattr.DecodeSeek(r)
+	var serr *rbxattr.SeekError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected SeekError, got %v", err)
+	}
+	if serr.Start != 3 {
+		t.Fatalf("expected start offset 3, got %d", serr.Start)
+	}
+	if pos, _ := r.Seek(0, io.SeekCurrent); pos != 3 {
+		t.Fatalf("expected reader at offset 3, got %d", pos)
+	}
+
+	dec := rbxattr.NewDecoder(r)
+	dec.Repair = true
+	model, err = dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, "retried", model.Value, "Name")
+}
+
+func TestDecoderWideFloats(t *testing.T) {
+	data := encode(t, rbxattr.ValueDictionary{
+		{Key: "A", Value: newFloat(0.1)},
+		{Key: "B", Value: newFloat(float32(math.Inf(-1)))},
+		{Key: "C", Value: newFloat(math.Float32frombits(0x7F800123))},
+		{Key: "D", Value: newFloat(math.Float32frombits(0xFFC00000))},
+		{Key: "E", Value: newFloat(math.Float32frombits(0x80000001))},
+	})
+
+	dec := rbxattr.NewDecoder(bytes.NewReader(data))
+	dec.WideFloats = true
+	model, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range model.Value {
+		if _, ok := entry.Value.(*rbxattr.ValueFloatWide); !ok {
+			t.Fatalf("%s: expected *ValueFloatWide, got %T", entry.Key, entry.Value)
+		}
+	}
+	if got := float64(*model.Value[0].Value.(*rbxattr.ValueFloatWide)); got != float64(float32(0.1)) {
+		t.Errorf("expected widened value %v, got %v", float64(float32(0.1)), got)
+	}
+
+	var buf bytes.Buffer
+	if _, err := model.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("re-encoded bytes differ\n\t%X\n\t%X", data, buf.Bytes())
+	}
+}
+
+func newFloat(f float32) *rbxattr.ValueFloat {
+	v := rbxattr.ValueFloat(f)
+	return &v
+}
+
+func TestUnsupportedTypes(t *testing.T) {
+	var buf bytes.Buffer
+	entry := func(key string, typ byte, value []byte) {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(key)))
+		buf.WriteString(key)
+		buf.WriteByte(typ)
+		buf.Write(value)
+	}
+	binary.Write(&buf, binary.LittleEndian, uint32(5))
+	entry("Name", byte(rbxattr.TypeString), []byte{3, 0, 0, 0, 'f', 'o', 'o'})
+	entry("Position", byte(rbxattr.TypeCFrame), append(make([]byte, 12), 2))
+	entry("Faces", 0x0C, []byte{0xFF, 0xFF})
+	entry("Region", 0x1F, []byte{0xFF, 0xFF, 0xFF, 0xFF})
+	entry("Other", 0x0C, nil)
+	data := buf.Bytes()
+
+	types, err := rbxattr.UnsupportedTypes(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(types) != 2 || types[0] != 0x0C || types[1] != 0x1F {
+		t.Fatalf("expected types 0x0C and 0x1F, got %v", types)
+	}
+
+	types, err = rbxattr.UnsupportedTypes(bytes.NewReader(encode(t, rbxattr.ValueDictionary{
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1}},
+	})))
+	if err != nil || len(types) != 0 {
+		t.Fatalf("expected no types, got %v, %v", types, err)
+	}
+	if _, err := rbxattr.UnsupportedTypes(bytes.NewReader(data[:20])); err == nil {
+		t.Fatal("expected error for truncated stream")
+	}
+}
+
+// flakyReader fails once when a read reaches offset FailAt.
+type flakyReader struct {
+	*bytes.Reader
+	FailAt int64
+	failed bool
+}
+
+var errFlaky = errors.New("flaky read")
+
+func (r *flakyReader) Read(p []byte) (n int, err error) {
+	pos := r.Size() - int64(r.Len())
+	if !r.failed {
+		if pos >= r.FailAt {
+			r.failed = true
+			return 0, errFlaky
+		}
+		if pos+int64(len(p)) > r.FailAt {
+			p = p[:r.FailAt-pos]
+		}
+	}
+	return r.Reader.Read(p)
+}
+
+func TestDecoderCheckpoint(t *testing.T) {
+	var dict rbxattr.ValueDictionary
+	for _, key := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		v := rbxattr.ValueString(key + key)
+		dict = append(dict, rbxattr.Entry{Key: key, Value: &v})
+	}
+	data := encode(t, dict)
+	// Each entry encodes to 12 bytes. Fail in the middle of the fifth.
+	r := &flakyReader{Reader: bytes.NewReader(data), FailAt: 4 + 4*12 + 7}
+
+	dec := rbxattr.NewDecoder(r)
+	count, err := dec.BeginDictionary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(dict) {
+		t.Fatalf("expected %d entries, got %d", len(dict), count)
+	}
+	var got rbxattr.ValueDictionary
+	for {
+		entry, err := dec.DecodeEntry()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !errors.Is(err, errFlaky) {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(got) != 4 {
+				t.Fatalf("expected failure on fifth entry, got %d entries", len(got))
+			}
+			if cp := dec.Checkpoint(); cp != 4+4*12 {
+				t.Fatalf("expected checkpoint %d, got %d", 4+4*12, cp)
+			}
+			if _, err := r.Seek(dec.Checkpoint(), io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		got = append(got, entry)
+	}
+	if !r.failed {
+		t.Fatal("expected an injected failure")
+	}
+	if !got.Equal(dict) {
+		t.Fatal("resumed entries do not match")
+	}
+	if dec.Checkpoint() != int64(len(data)) {
+		t.Fatalf("expected final checkpoint %d, got %d", len(data), dec.Checkpoint())
+	}
+}
+
+func TestDecoderEntryBytes(t *testing.T) {
+	a := rbxattr.ValueString("foo")
+	b := rbxattr.ValueBool(true)
+	dict := rbxattr.ValueDictionary{
+		{Key: "LongerName", Value: &a},
+		{Key: "B", Value: &b},
+	}
+	data := encode(t, dict)
+	dec := rbxattr.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.BeginDictionary(); err != nil {
+		t.Fatal(err)
+	}
+	var got rbxattr.ValueDictionary
+	for {
+		key, value, err := dec.DecodeEntryBytes()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, rbxattr.Entry{Key: string(key), Value: value})
+	}
+	if !got.Equal(dict) {
+		t.Fatal("decoded entries do not match")
+	}
+	if dec.Checkpoint() != int64(len(data)) {
+		t.Fatalf("expected checkpoint %d, got %d", len(data), dec.Checkpoint())
+	}
+}
+
+func TestDecoderHugeKey(t *testing.T) {
+	// A key that declares far more bytes than remain is not allocated in full.
+	data := []byte{1, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, 'K', 'e', 'y'}
+	for name, decode := range map[string]func(*rbxattr.Decoder) error{
+		"DecodeEntryBytes": func(dec *rbxattr.Decoder) error {
+			if _, err := dec.BeginDictionary(); err != nil {
+				return err
+			}
+			_, _, err := dec.DecodeEntryBytes()
+			return err
+		},
+		"InternStrings": func(dec *rbxattr.Decoder) error {
+			dec.InternStrings = true
+			_, err := dec.Decode()
+			return err
+		},
+		"Decode": func(dec *rbxattr.Decoder) error {
+			_, err := dec.Decode()
+			return err
+		},
+	} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := decode(rbxattr.NewDecoder(bytes.NewReader(data)))
+		runtime.ReadMemStats(&after)
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: expected unexpected EOF, got %v", name, err)
+		}
+		if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+			t.Errorf("%s: expected small allocation, got %d bytes", name, alloc)
+		}
+	}
+}
+
+func TestDecoderOnEntry(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	b := rbxattr.ValueBool(true)
+	data := encode(t, rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Enabled", Value: &b},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+	})
+	type call struct {
+		key  string
+		typ  rbxattr.Type
+		size int64
+	}
+	want := []call{
+		{"Name", rbxattr.TypeString, 4 + 4 + 1 + 4 + 6},
+		{"Enabled", rbxattr.TypeBool, 4 + 7 + 1 + 1},
+		{"Size", rbxattr.TypeUDim2, 4 + 4 + 1 + 16},
+	}
+	var calls []call
+	dec := rbxattr.NewDecoder(bytes.NewReader(data))
+	dec.OnEntry = func(key string, typ rbxattr.Type, size int64) {
+		calls = append(calls, call{key, typ, size})
+	}
+	if _, err := dec.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls, got %d", len(want), len(calls))
+	}
+	var total int64 = 4
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d: expected %v, got %v", i, want[i], calls[i])
+		}
+		total += calls[i].size
+	}
+	if total != int64(len(data)) {
+		t.Errorf("expected sizes to total %d, got %d", len(data), total)
+	}
+}
+
+func TestDecoderInternStrings(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+		{Key: "Label", Value: &s},
+	}
+	data := encode(t, dict)
+	stream := append(append([]byte(nil), data...), data...)
+	dec := rbxattr.NewDecoder(bytes.NewReader(stream))
+	dec.InternStrings = true
+	for i := 0; i < 2; i++ {
+		model, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("model %d: %s", i, err)
+		}
+		if !model.Value.Equal(dict) {
+			t.Errorf("model %d: decoded model does not match", i)
+		}
+	}
+
+	dec = rbxattr.NewDecoder(bytes.NewReader(data[:len(data)-2]))
+	dec.InternStrings = true
+	_, err := dec.Decode()
+	const want = `format: offset 66: Dictionary[2]("Label") value: String: unexpected EOF`
+	if err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+	if _, err := rbxattr.NewDecoder(bytes.NewReader(data[:len(data)-2])).Decode(); err == nil || err.Error() != want {
+		t.Errorf("expected plain error %q, got %v", want, err)
+	}
+}
+
+func TestOffsetOf(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/udim2.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Length, then Size entry (4+4+1+16), then Position key and type.
+	const want = 4 + 25 + 4 + 8 + 1
+	offset, ok := rbxattr.OffsetOf(b, "Position")
+	if !ok || offset != want {
+		t.Fatalf("expected offset %d, got %d, %v", want, offset, ok)
+	}
+	var v rbxattr.ValueUDim2
+	if _, err := v.ReadFrom(bytes.NewReader(b[offset:])); err != nil {
+		t.Fatal(err)
+	}
+	if v.X.Scale != 0.25 || v.X.Offset != -50 {
+		t.Errorf("unexpected value %v at offset", v)
+	}
+	if _, ok := rbxattr.OffsetOf(b, "Missing"); ok {
+		t.Error("expected missing key to not be found")
+	}
+	if _, ok := rbxattr.OffsetOf(b[:30], "Position"); ok {
+		t.Error("expected truncated input to not be found")
+	}
+}
+
+func TestDecoderStrictFloats(t *testing.T) {
+	snan := rbxattr.ValueFloat(math.Float32frombits(0x7F800001))
+	qnan := rbxattr.ValueFloat(math.Float32frombits(0x7FC00001))
+	denormal := rbxattr.ValueDouble(math.Float64frombits(1))
+	for _, c := range []struct {
+		name   string
+		dict   rbxattr.ValueDictionary
+		strict string
+		denorm string
+	}{
+		{
+			name:   "Float",
+			dict:   rbxattr.ValueDictionary{{Key: "Quiet", Value: &qnan}, {Key: "Value", Value: &snan}},
+			strict: `format: offset 32: Dictionary[1]("Value") value: Float: signaling NaN 7F800001`,
+		},
+		{
+			name: "Vector3",
+			dict: rbxattr.ValueDictionary{{Key: "Position", Value: &rbxattr.ValueVector3{
+				Y: math.Float32frombits(0xFFBFFFFF),
+			}}},
+			strict: `format: offset 29: Dictionary[0]("Position") value: Vector3.Y: signaling NaN FFBFFFFF`,
+		},
+		{
+			name:   "Double",
+			dict:   rbxattr.ValueDictionary{{Key: "Tiny", Value: &denormal}},
+			denorm: `format: offset 21: Dictionary[0]("Tiny") value: Double: denormal 0000000000000001`,
+		},
+	} {
+		data := encode(t, c.dict)
+		model, err := rbxattr.NewDecoder(bytes.NewReader(data)).Decode()
+		if err != nil {
+			t.Errorf("%s: permissive: %s", c.name, err)
+		} else if !model.Value.Equal(c.dict) {
+			t.Errorf("%s: permissive: decoded model does not match", c.name)
+		}
+
+		for _, mode := range []struct {
+			strict, denorm bool
+			want           string
+		}{
+			{true, false, c.strict},
+			{false, true, c.denorm},
+		} {
+			dec := rbxattr.NewDecoder(bytes.NewReader(data))
+			dec.StrictFloats = mode.strict
+			dec.RejectDenormals = mode.denorm
+			_, err := dec.Decode()
+			switch {
+			case mode.want == "" && err != nil:
+				t.Errorf("%s: unexpected error: %s", c.name, err)
+			case mode.want != "" && (err == nil || err.Error() != mode.want):
+				t.Errorf("%s: expected error %q, got %v", c.name, mode.want, err)
+			}
+		}
+	}
+}
+
+// blockingReader blocks each read until Release is closed, then reads from R.
+type blockingReader struct {
+	R       io.Reader
+	Release chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (n int, err error) {
+	<-r.Release
+	return r.R.Read(p)
+}
+
+func TestDecodeTimeout(t *testing.T) {
+	dict := rbxattr.ValueDictionary{
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+	}
+	data := encode(t, dict)
+
+	model, err := rbxattr.DecodeTimeout(bytes.NewReader(data), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !model.Value.Equal(dict) {
+		t.Error("decoded model does not match")
+	}
+
+	r := &blockingReader{R: bytes.NewReader(data), Release: make(chan struct{})}
+	defer close(r.Release)
+	_, err = rbxattr.DecodeTimeout(r, 10*time.Millisecond)
+	var terr *rbxattr.TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+	if terr.Duration != 10*time.Millisecond || !terr.Timeout() {
+		t.Errorf("unexpected timeout error %#v", terr)
+	}
+	if want := "format: decode timed out after 10ms"; err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err)
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/sample.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model rbxattr.Model
+	if _, err := model.ReadFrom(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := rbxattr.ListKeys(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(model.Value) {
+		t.Fatalf("expected %d keys, got %d", len(model.Value), len(keys))
+	}
+	for i, entry := range model.Value {
+		if keys[i] != entry.Key {
+			t.Errorf("key %d: expected %q, got %q", i, entry.Key, keys[i])
+		}
+	}
+
+	if _, err := rbxattr.ListKeys(bytes.NewReader(b[:len(b)-1])); err == nil {
+		t.Error("truncated: expected error")
+	}
+	corrupt := append([]byte(nil), b...)
+	corrupt[12] = 0x1F
+	const want = `format: Dictionary[0]("MMMM") value: unknown data type 0x1F`
+	if _, err := rbxattr.ListKeys(bytes.NewReader(corrupt)); err == nil || err.Error() != want {
+		t.Errorf("corrupt type: expected error %q, got %v", want, err)
+	}
+
+	// An over-declared length must fail without preallocating every key.
+	if _, err := rbxattr.ListKeys(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF})); !errors.Is(err, io.EOF) {
+		t.Errorf("over-declared: expected EOF, got %v", err)
+	}
+}
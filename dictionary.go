@@ -0,0 +1,425 @@
+package rbxattr
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReservedPrefix is the key prefix reserved by Roblox for internal attributes.
+const ReservedPrefix = "RBX"
+
+// IsReserved returns whether key begins with ReservedPrefix.
+func IsReserved(key string) bool {
+	return strings.HasPrefix(key, ReservedPrefix)
+}
+
+// StripReserved returns a copy of v without entries that have a reserved key.
+func (v ValueDictionary) StripReserved() ValueDictionary {
+	d := make(ValueDictionary, 0, len(v))
+	for _, entry := range v {
+		if !IsReserved(entry.Key) {
+			d = append(d, entry)
+		}
+	}
+	return d
+}
+
+// Reserved returns a copy of v containing only entries that have a reserved
+// key.
+func (v ValueDictionary) Reserved() ValueDictionary {
+	d := make(ValueDictionary, 0)
+	for _, entry := range v {
+		if IsReserved(entry.Key) {
+			d = append(d, entry)
+		}
+	}
+	return d
+}
+
+// DedupeLast returns a copy of v in which only the last entry of each key is
+// retained, in its original position. This is the opposite of the first-wins
+// resolution of duplicate keys used elsewhere, such as by Get.
+func (v ValueDictionary) DedupeLast() ValueDictionary {
+	last := make(map[string]int, len(v))
+	for i, entry := range v {
+		last[entry.Key] = i
+	}
+	d := make(ValueDictionary, 0, len(last))
+	for i, entry := range v {
+		if last[entry.Key] == i {
+			d = append(d, entry)
+		}
+	}
+	return d
+}
+
+// Namespace returns a copy of v containing only entries with a key that
+// begins with prefix followed by ".", with that part of the key removed. It
+// reverses Prefixed.
+func (v ValueDictionary) Namespace(prefix string) ValueDictionary {
+	prefix += "."
+	d := make(ValueDictionary, 0)
+	for _, entry := range v {
+		if strings.HasPrefix(entry.Key, prefix) {
+			d = append(d, Entry{Key: entry.Key[len(prefix):], Value: entry.Value})
+		}
+	}
+	return d
+}
+
+// Prefixed returns a copy of v in which each key is preceded by prefix
+// followed by ".". It reverses Namespace. Because Roblox does not allow "." in
+// attribute names, the result is intended for composing dictionaries, and
+// should be namespaced again before being encoded for Roblox.
+func (v ValueDictionary) Prefixed(prefix string) ValueDictionary {
+	d := make(ValueDictionary, len(v))
+	for i, entry := range v {
+		d[i] = Entry{Key: prefix + "." + entry.Key, Value: entry.Value}
+	}
+	return d
+}
+
+// MapKeys returns a copy of v in which each key is replaced by the result of
+// fn, preserving the order and values of entries. If multiple entries map to
+// the same key, only the first is retained, consistent with the resolution of
+// duplicate keys by Get.
+func (v ValueDictionary) MapKeys(fn func(string) string) ValueDictionary {
+	d := make(ValueDictionary, 0, len(v))
+	seen := make(map[string]bool, len(v))
+	for _, entry := range v {
+		key := fn(entry.Key)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		d = append(d, Entry{Key: key, Value: entry.Value})
+	}
+	return d
+}
+
+// CaseCollisions returns each group of distinct keys in v that differ only by
+// case, such as "Color" and "color". Such keys are distinct to Roblox, but
+// collide in systems that fold case. Keys within a group, and the groups
+// themselves, are in order of first appearance.
+func (v ValueDictionary) CaseCollisions() [][]string {
+	var folds []string
+	groups := map[string][]string{}
+	seen := map[string]bool{}
+	for _, entry := range v {
+		if seen[entry.Key] {
+			continue
+		}
+		seen[entry.Key] = true
+		fold := strings.ToLower(entry.Key)
+		if _, ok := groups[fold]; !ok {
+			folds = append(folds, fold)
+		}
+		groups[fold] = append(groups[fold], entry.Key)
+	}
+	var collisions [][]string
+	for _, fold := range folds {
+		if len(groups[fold]) > 1 {
+			collisions = append(collisions, groups[fold])
+		}
+	}
+	return collisions
+}
+
+// Equal returns whether v and u contain the same keys with equal values in the
+// same order. Values are equal when they have the same type and encode to the
+// same bytes, so floats are compared bitwise. In particular, negative zero and
+// positive zero are distinct. The NormalizeNegativeZero option of Encoder can
+// be used to encode them identically.
+func (v ValueDictionary) Equal(u ValueDictionary) bool {
+	return v.equal(u, valueEqual)
+}
+
+// EqualSequences is like Equal, except that the Envelope, Time, and Value
+// fields of NumberSequence and ColorSequence keypoints are considered equal
+// when they differ by no more than tolerance.
+func (v ValueDictionary) EqualSequences(u ValueDictionary, tolerance float32) bool {
+	return v.equal(u, func(a, b Value) bool {
+		return sequenceEqual(a, b, tolerance)
+	})
+}
+
+func (v ValueDictionary) equal(u ValueDictionary, eq func(a, b Value) bool) bool {
+	if len(v) != len(u) {
+		return false
+	}
+	for i := range v {
+		if v[i].Key != u[i].Key || !eq(v[i].Value, u[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// valueEqual returns whether a and b have the same type and encoding.
+func valueEqual(a, b Value) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	var wa, wb bytes.Buffer
+	if _, err := a.WriteTo(&wa); err != nil {
+		return false
+	}
+	if _, err := b.WriteTo(&wb); err != nil {
+		return false
+	}
+	return bytes.Equal(wa.Bytes(), wb.Bytes())
+}
+
+// sequenceEqual compares the keypoints of sequences within tolerance, falling
+// back to valueEqual for other values.
+func sequenceEqual(a, b Value, tolerance float32) bool {
+	near := func(x, y float32) bool {
+		return math.Float32bits(x) == math.Float32bits(y) ||
+			math.Abs(float64(x)-float64(y)) <= float64(tolerance)
+	}
+	switch a := a.(type) {
+	case *ValueNumberSequence:
+		b, ok := b.(*ValueNumberSequence)
+		if !ok || len(*a) != len(*b) {
+			return false
+		}
+		for i, ka := range *a {
+			kb := (*b)[i]
+			if !near(ka.Envelope, kb.Envelope) ||
+				!near(ka.Time, kb.Time) ||
+				!near(ka.Value, kb.Value) {
+				return false
+			}
+		}
+		return true
+	case *ValueColorSequence:
+		b, ok := b.(*ValueColorSequence)
+		if !ok || len(*a) != len(*b) {
+			return false
+		}
+		for i, ka := range *a {
+			kb := (*b)[i]
+			if !near(ka.Envelope, kb.Envelope) ||
+				!near(ka.Time, kb.Time) ||
+				!near(ka.Value.R, kb.Value.R) ||
+				!near(ka.Value.G, kb.Value.G) ||
+				!near(ka.Value.B, kb.Value.B) {
+				return false
+			}
+		}
+		return true
+	}
+	return valueEqual(a, b)
+}
+
+// Get returns the value of the first entry in v with the given key. Returns
+// false if no entry has the key.
+func (v ValueDictionary) Get(key string) (Value, bool) {
+	for _, entry := range v {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Index returns a map of each key in v to the index of the first entry with
+// that key. The index can be passed to GetIndexed for repeated lookups. It
+// becomes stale if v is modified.
+func (v ValueDictionary) Index() map[string]int {
+	idx := make(map[string]int, len(v))
+	for i, entry := range v {
+		if _, ok := idx[entry.Key]; !ok {
+			idx[entry.Key] = i
+		}
+	}
+	return idx
+}
+
+// GetIndexed is like Get, but uses idx, as returned by Index, to locate the
+// entry.
+func (v ValueDictionary) GetIndexed(idx map[string]int, key string) (Value, bool) {
+	i, ok := idx[key]
+	if !ok || i < 0 || i >= len(v) {
+		return nil, false
+	}
+	return v[i].Value, true
+}
+
+// MergeSequences returns a sequence containing the keypoints of each entry in
+// v with the given key, concatenated in order of appearance. Each entry must
+// be a NumberSequence, or each must be a ColorSequence. The times of keypoints
+// are not adjusted, so the result may need to be rescaled before it is a valid
+// sequence for Roblox. Returns false if no entry has the key, or if the values
+// of the entries are not sequences of the same type.
+func (v ValueDictionary) MergeSequences(key string) (Value, bool) {
+	var numbers ValueNumberSequence
+	var colors ValueColorSequence
+	var t Type
+	for _, entry := range v {
+		if entry.Key != key {
+			continue
+		}
+		switch s := entry.Value.(type) {
+		case *ValueNumberSequence:
+			if t != 0 && t != TypeNumberSequence {
+				return nil, false
+			}
+			t = TypeNumberSequence
+			numbers = append(numbers, *s...)
+		case *ValueColorSequence:
+			if t != 0 && t != TypeColorSequence {
+				return nil, false
+			}
+			t = TypeColorSequence
+			colors = append(colors, *s...)
+		default:
+			return nil, false
+		}
+	}
+	switch t {
+	case TypeNumberSequence:
+		return &numbers, true
+	case TypeColorSequence:
+		return &colors, true
+	}
+	return nil, false
+}
+
+// SortedEntries returns a copy of the entries of v, sorted by key. Entries
+// with equal keys retain their relative order. v is not modified.
+func (v ValueDictionary) SortedEntries() []Entry {
+	entries := make([]Entry, len(v))
+	copy(entries, v)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// IsSorted returns whether the entries of v are ordered by key, comparing keys
+// byte-wise, as produced by SortedEntries. Entries with equal keys may appear
+// in any order.
+func (v ValueDictionary) IsSorted() bool {
+	for i := 1; i < len(v); i++ {
+		if v[i].Key < v[i-1].Key {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a deep copy of v. Modifying the copy, including the content of
+// its values, does not affect v. A value of a type with unexported fields,
+// which cannot be copied, is shared with v instead.
+func (v ValueDictionary) Clone() ValueDictionary {
+	if v == nil {
+		return nil
+	}
+	d := make(ValueDictionary, len(v))
+	for i, entry := range v {
+		d[i] = Entry{Key: entry.Key, Value: cloneValue(entry.Value)}
+	}
+	return d
+}
+
+// cloneValue returns a deep copy of v, or v itself if v cannot be copied.
+func cloneValue(v Value) Value {
+	rv := reflect.ValueOf(v)
+	if !canClone(rv) {
+		return v
+	}
+	c := reflect.New(rv.Elem().Type())
+	deepCopy(c.Elem(), rv.Elem())
+	return c.Interface().(Value)
+}
+
+// canClone returns whether rv is a non-nil pointer to a value that deepCopy
+// can copy, which requires every struct field within the value to be exported.
+func canClone(rv reflect.Value) bool {
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return false
+	}
+	return exportedOnly(rv.Type().Elem(), map[reflect.Type]bool{})
+}
+
+// exportedOnly returns whether every struct field reachable within t through
+// structs, arrays, and slices is exported. seen holds the types already
+// visited, which are assumed to qualify.
+func exportedOnly(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return exportedOnly(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath != "" || !exportedOnly(f.Type, seen) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// deepCopy sets dst to a copy of src that shares no slices with src.
+func deepCopy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(src.Type()))
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			deepCopy(dst.Field(i), src.Field(i))
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// AsArray returns the values of v ordered by key, if every key is a decimal
+// integer, and the keys are contiguous, starting from either 0 or 1. Keys must
+// be in canonical form, without signs or leading zeros. Returns false if v is
+// not shaped like an array. An empty dictionary is an empty array.
+func (v ValueDictionary) AsArray() ([]Value, bool) {
+	values := make([]Value, len(v)+1)
+	for _, entry := range v {
+		i, err := strconv.Atoi(entry.Key)
+		if err != nil || i < 0 || i > len(v) || strconv.Itoa(i) != entry.Key || values[i] != nil {
+			return nil, false
+		}
+		if entry.Value == nil {
+			return nil, false
+		}
+		values[i] = entry.Value
+	}
+	// With n entries in range [0, n], exactly one index is unused, which must
+	// be either the first or the last.
+	switch {
+	case values[0] == nil:
+		return values[1:], true
+	case values[len(v)] == nil:
+		return values[:len(v)], true
+	}
+	return nil, false
+}
@@ -0,0 +1,401 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func keys(d rbxattr.ValueDictionary) []string {
+	k := make([]string, len(d))
+	for i, entry := range d {
+		k[i] = entry.Key
+	}
+	return k
+}
+
+func expectKeys(t *testing.T, name string, d rbxattr.ValueDictionary, want ...string) {
+	t.Helper()
+	got := keys(d)
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected keys %q, got %q", name, want, got)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: expected keys %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestDictionaryReserved(t *testing.T) {
+	b := rbxattr.ValueBool(true)
+	d := rbxattr.ValueDictionary{
+		{Key: "Health", Value: &b},
+		{Key: "RBX_Internal", Value: &b},
+		{Key: "Rbx", Value: &b},
+		{Key: "RBXState", Value: &b},
+		{Key: "Team", Value: &b},
+	}
+	expectKeys(t, "StripReserved", d.StripReserved(), "Health", "Rbx", "Team")
+	expectKeys(t, "Reserved", d.Reserved(), "RBX_Internal", "RBXState")
+	expectKeys(t, "original", d, "Health", "RBX_Internal", "Rbx", "RBXState", "Team")
+}
+
+func TestDictionaryEqual(t *testing.T) {
+	seq := func(envelope float32) *rbxattr.ValueNumberSequence {
+		return &rbxattr.ValueNumberSequence{
+			{Envelope: 0, Time: 0, Value: 1},
+			{Envelope: envelope, Time: 1, Value: 2},
+		}
+	}
+	colors := func(envelope float32) *rbxattr.ValueColorSequence {
+		return &rbxattr.ValueColorSequence{
+			{Envelope: envelope, Time: 0, Value: rbxattr.ValueColor3{R: 1}},
+		}
+	}
+	s := rbxattr.ValueString("foo")
+	a := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Numbers", Value: seq(0.123456)},
+		{Key: "Colors", Value: colors(0.5)},
+	}
+	b := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Numbers", Value: seq(0.123457)},
+		{Key: "Colors", Value: colors(0.5000001)},
+	}
+
+	if !a.Equal(a) {
+		t.Error("expected dictionary to equal itself")
+	}
+	if a.Equal(b) {
+		t.Error("expected dictionaries to differ exactly")
+	}
+	if !a.EqualSequences(b, 1e-5) {
+		t.Error("expected dictionaries to be equal within tolerance")
+	}
+	if a.EqualSequences(b, 1e-7) {
+		t.Error("expected dictionaries to differ beyond tolerance")
+	}
+
+	other := rbxattr.ValueString("bar")
+	c := rbxattr.ValueDictionary{a[0], a[1], {Key: "Colors", Value: &other}}
+	if a.EqualSequences(c, 1) {
+		t.Error("expected differing value types to be unequal")
+	}
+}
+
+func TestDictionaryIndex(t *testing.T) {
+	a := rbxattr.ValueString("a")
+	b := rbxattr.ValueString("b")
+	c := rbxattr.ValueString("c")
+	d := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &a},
+		{Key: "Title", Value: &b},
+		{Key: "Name", Value: &c},
+	}
+	idx := d.Index()
+	if len(idx) != 2 {
+		t.Fatalf("expected 2 indexed keys, got %d", len(idx))
+	}
+	for _, key := range []string{"Name", "Title", "Missing"} {
+		want, wantOK := d.Get(key)
+		got, gotOK := d.GetIndexed(idx, key)
+		if got != want || gotOK != wantOK {
+			t.Errorf("%s: Get returned %v, %t; GetIndexed returned %v, %t", key, want, wantOK, got, gotOK)
+		}
+	}
+	if v, _ := d.Get("Name"); v != &a {
+		t.Error("expected first entry to win")
+	}
+}
+
+func TestDictionarySortedEntries(t *testing.T) {
+	a := rbxattr.ValueString("a")
+	b := rbxattr.ValueString("b")
+	d := rbxattr.ValueDictionary{
+		{Key: "b", Value: &a},
+		{Key: "C", Value: &a},
+		{Key: "a", Value: &a},
+		{Key: "b", Value: &b},
+	}
+	sorted := d.SortedEntries()
+	expectKeys(t, "sorted", sorted, "C", "a", "b", "b")
+	if sorted[2].Value != &a || sorted[3].Value != &b {
+		t.Error("expected equal keys to retain their order")
+	}
+	expectKeys(t, "original", d, "b", "C", "a", "b")
+}
+
+func TestDictionaryAsArray(t *testing.T) {
+	a := rbxattr.ValueString("a")
+	b := rbxattr.ValueString("b")
+	c := rbxattr.ValueString("c")
+	dict := func(keys ...string) rbxattr.ValueDictionary {
+		values := []rbxattr.Value{&a, &b, &c}
+		d := rbxattr.ValueDictionary{}
+		for i, key := range keys {
+			d = append(d, rbxattr.Entry{Key: key, Value: values[i]})
+		}
+		return d
+	}
+	for _, keys := range [][]string{
+		{"1", "2", "3"},
+		{"0", "1", "2"},
+		{"2", "3", "1"},
+	} {
+		values, ok := dict(keys...).AsArray()
+		if !ok {
+			t.Errorf("%q: expected array", keys)
+			continue
+		}
+		if len(values) != 3 {
+			t.Errorf("%q: expected 3 values, got %d", keys, len(values))
+		}
+	}
+	if values, ok := dict("2", "3", "1").AsArray(); !ok || values[0] != &c || values[1] != &a || values[2] != &b {
+		t.Error("expected values ordered by key")
+	}
+	if values, ok := dict().AsArray(); !ok || len(values) != 0 {
+		t.Error("expected empty array")
+	}
+	for _, keys := range [][]string{
+		{"1", "2", "4"},
+		{"1", "3"},
+		{"0", "2"},
+		{"1", "1"},
+		{"1", "01"},
+		{"1", "+2"},
+		{"-1", "0"},
+		{"1", "x"},
+		{"2", "3"},
+	} {
+		if _, ok := dict(keys...).AsArray(); ok {
+			t.Errorf("%q: expected not an array", keys)
+		}
+	}
+}
+
+func TestDictionaryReadEntries(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+	}
+	var buf bytes.Buffer
+	if _, err := dict.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	entries := buf.Bytes()[4:]
+
+	var got rbxattr.ValueDictionary
+	n, err := got.ReadEntries(bytes.NewReader(entries), len(dict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(entries)) {
+		t.Errorf("expected %d bytes read, got %d", len(entries), n)
+	}
+	if !got.Equal(dict) {
+		t.Error("decoded entries do not match")
+	}
+
+	if _, err := got.ReadEntries(bytes.NewReader(entries), 3); err == nil {
+		t.Error("expected error when count exceeds entries")
+	}
+	if n, err := got.ReadEntries(bytes.NewReader(nil), 0); err != nil || n != 0 || got == nil || len(got) != 0 {
+		t.Errorf("expected empty dictionary, got %v, %d, %v", got, n, err)
+	}
+	if _, err := got.ReadEntries(bytes.NewReader(entries), math.MaxUint32); err == nil {
+		t.Error("expected error when huge count exceeds entries")
+	}
+}
+
+func TestDictionaryWriteEntries(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{X: rbxattr.ValueUDim{Scale: 1}}},
+	}
+	var buf bytes.Buffer
+	n, err := dict.WriteEntries(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) || n != (rbxattr.Model{Value: dict}).Size()-4 {
+		t.Errorf("expected %d bytes written, got %d", (rbxattr.Model{Value: dict}).Size()-4, n)
+	}
+
+	// The count is supplied separately from the entries.
+	count := len(dict)
+	var got rbxattr.ValueDictionary
+	if m, err := got.ReadEntries(&buf, count); err != nil {
+		t.Fatal(err)
+	} else if m != n {
+		t.Errorf("expected %d bytes read, got %d", n, m)
+	}
+	if !got.Equal(dict) {
+		t.Error("decoded entries do not match")
+	}
+}
+
+func TestDictionaryDedupeLast(t *testing.T) {
+	first := rbxattr.ValueString("first")
+	last := rbxattr.ValueString("last")
+	b := rbxattr.ValueBool(true)
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &first},
+		{Key: "Enabled", Value: &b},
+		{Key: "Name", Value: &last},
+		{Key: "Visible", Value: &b},
+	}
+	got := dict.DedupeLast()
+	expectKeys(t, "DedupeLast", got, "Enabled", "Name", "Visible")
+	if v, _ := got.Get("Name"); v != &last {
+		t.Errorf("expected last value to survive, got %v", v)
+	}
+	if len(dict) != 4 {
+		t.Error("original dictionary was modified")
+	}
+}
+
+func TestDictionaryPrefixed(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	b := rbxattr.ValueBool(true)
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Enabled", Value: &b},
+	}
+	prefixed := dict.Prefixed("Door")
+	expectKeys(t, "Prefixed", prefixed, "Door.Name", "Door.Enabled")
+	expectKeys(t, "Prefixed", dict, "Name", "Enabled")
+
+	composed := append(prefixed, rbxattr.Entry{Key: "Window.Name", Value: &s}, rbxattr.Entry{Key: "Doorway", Value: &b})
+	got := composed.Namespace("Door")
+	if !got.Equal(dict) {
+		t.Errorf("expected keys %q, got %q", keys(dict), keys(got))
+	}
+	expectKeys(t, "Namespace", composed.Namespace("Window"), "Name")
+}
+
+func TestDictionaryCaseCollisions(t *testing.T) {
+	b := rbxattr.ValueBool(true)
+	var dict rbxattr.ValueDictionary
+	for _, key := range []string{"Color", "Name", "color", "Size", "COLOR", "Color", "name"} {
+		dict = append(dict, rbxattr.Entry{Key: key, Value: &b})
+	}
+	got := dict.CaseCollisions()
+	want := [][]string{{"Color", "color", "COLOR"}, {"Name", "name"}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if strings.Join(got[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("group %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+	if got := dict[:2].CaseCollisions(); len(got) != 0 {
+		t.Errorf("expected no collisions, got %q", got)
+	}
+}
+
+func TestDictionaryMapKeys(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	b := rbxattr.ValueBool(true)
+	d := rbxattr.ValueDouble(1)
+	dict := rbxattr.ValueDictionary{
+		{Key: "display_name", Value: &s},
+		{Key: "is_enabled", Value: &b},
+		{Key: "speed", Value: &d},
+		{Key: "Speed", Value: &b},
+	}
+	pascal := func(key string) string {
+		parts := strings.Split(key, "_")
+		for i, part := range parts {
+			if part != "" {
+				parts[i] = strings.ToUpper(part[:1]) + part[1:]
+			}
+		}
+		return strings.Join(parts, "")
+	}
+	got := dict.MapKeys(pascal)
+	expectKeys(t, "MapKeys", got, "DisplayName", "IsEnabled", "Speed")
+	for i, entry := range got {
+		if entry.Value != dict[i].Value {
+			t.Errorf("entry %d: value not preserved", i)
+		}
+	}
+	expectKeys(t, "MapKeys", dict, "display_name", "is_enabled", "speed", "Speed")
+}
+
+func TestDictionaryIsSorted(t *testing.T) {
+	b := rbxattr.ValueBool(true)
+	for _, c := range []struct {
+		keys   []string
+		sorted bool
+	}{
+		{nil, true},
+		{[]string{"a"}, true},
+		{[]string{"C", "a", "b", "b"}, true},
+		{[]string{"Size", "Size2", "Sizes"}, true},
+		{[]string{"a", "C"}, false},
+		{[]string{"a", "b", "a"}, false},
+	} {
+		var d rbxattr.ValueDictionary
+		for _, key := range c.keys {
+			d = append(d, rbxattr.Entry{Key: key, Value: &b})
+		}
+		if got := d.IsSorted(); got != c.sorted {
+			t.Errorf("%q: expected %v, got %v", c.keys, c.sorted, got)
+		}
+		if !rbxattr.ValueDictionary(d.SortedEntries()).IsSorted() {
+			t.Errorf("%q: expected sorted entries to be sorted", c.keys)
+		}
+	}
+}
+
+func TestDictionaryMergeSequences(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	d := rbxattr.ValueDictionary{
+		{Key: "Curve", Value: &rbxattr.ValueNumberSequence{
+			{Time: 0, Value: 1},
+			{Time: 1, Value: 2},
+		}},
+		{Key: "Name", Value: &s},
+		{Key: "Curve", Value: &rbxattr.ValueNumberSequence{
+			{Time: 0, Value: 3},
+			{Envelope: 0.5, Time: 1, Value: 4},
+		}},
+	}
+	v, ok := d.MergeSequences("Curve")
+	if !ok {
+		t.Fatal("expected sequences to merge")
+	}
+	want := &rbxattr.ValueNumberSequence{
+		{Time: 0, Value: 1},
+		{Time: 1, Value: 2},
+		{Time: 0, Value: 3},
+		{Envelope: 0.5, Time: 1, Value: 4},
+	}
+	got := rbxattr.ValueDictionary{{Key: "Curve", Value: v}}
+	if !got.Equal(rbxattr.ValueDictionary{{Key: "Curve", Value: want}}) {
+		t.Errorf("expected %v, got %v", *want, v)
+	}
+	if len(*d[0].Value.(*rbxattr.ValueNumberSequence)) != 2 {
+		t.Error("expected original sequence to be unmodified")
+	}
+
+	if _, ok := d.MergeSequences("Missing"); ok {
+		t.Error("expected missing key to fail")
+	}
+	if _, ok := d.MergeSequences("Name"); ok {
+		t.Error("expected non-sequence value to fail")
+	}
+	d = append(d, rbxattr.Entry{Key: "Curve", Value: &rbxattr.ValueColorSequence{{Time: 0}}})
+	if _, ok := d.MergeSequences("Curve"); ok {
+		t.Error("expected mixed sequence types to fail")
+	}
+}
@@ -0,0 +1,239 @@
+package rbxattr
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// change describes how an attribute differs between two dictionaries. Old is
+// nil if the attribute was added, and New is nil if the attribute was removed.
+type change struct {
+	Key string
+	Old Value
+	New Value
+}
+
+// diff returns the changes that transform a into b. Only the first entry of
+// each key is considered. Removed attributes are listed first, in the order of
+// a, followed by changed and added attributes, in the order of b.
+func diff(a, b ValueDictionary) []change {
+	ai := a.Index()
+	bi := b.Index()
+	var changes []change
+	for i, entry := range a {
+		if ai[entry.Key] != i {
+			continue
+		}
+		if _, ok := bi[entry.Key]; !ok {
+			changes = append(changes, change{Key: entry.Key, Old: entry.Value})
+		}
+	}
+	for i, entry := range b {
+		if bi[entry.Key] != i {
+			continue
+		}
+		if j, ok := ai[entry.Key]; !ok {
+			changes = append(changes, change{Key: entry.Key, New: entry.Value})
+		} else if !valueEqual(a[j].Value, entry.Value) {
+			changes = append(changes, change{Key: entry.Key, Old: a[j].Value, New: entry.Value})
+		}
+	}
+	return changes
+}
+
+// patchVersion is the version of the format produced by MakePatch.
+const patchVersion = 1
+
+// Kinds of operation within a patch.
+const (
+	patchCopy   = 0 // Copy a run of entries from the old model.
+	patchInsert = 1 // Insert a run of entries from the patch.
+)
+
+// patchOp is a run of consecutive entries of the model produced by a patch.
+type patchOp struct {
+	kind  uint8
+	start uint32 // Index of the first entry in the old model, for patchCopy.
+	count uint32
+}
+
+// MakePatch returns a compact binary patch that transforms old into new when
+// passed to ApplyPatch. The patch contains only the attributes that were
+// added or changed, and refers to unchanged attributes by their position in
+// old, so that the order of new, including any duplicated keys, is
+// reproduced. The nth entry of a key in new is unchanged if it is equal to the
+// nth entry of that key in old.
+//
+// The patch begins with a version byte, followed by the number of operations
+// as a uint32, and each operation, followed by a Dictionary of the added and
+// changed attributes. An operation is a kind byte followed by uint32 fields. A
+// copy operation (0) has the index of the first entry in old and the number of
+// entries to copy. An insert operation (1) has the number of entries to take,
+// in order, from the Dictionary.
+func MakePatch(old, new Model) []byte {
+	occurrences := map[string][]int{}
+	for i, entry := range old.Value {
+		occurrences[entry.Key] = append(occurrences[entry.Key], i)
+	}
+	var ops []patchOp
+	var set ValueDictionary
+	for _, entry := range new.Value {
+		i := -1
+		if o := occurrences[entry.Key]; len(o) > 0 {
+			occurrences[entry.Key] = o[1:]
+			if valueEqual(old.Value[o[0]].Value, entry.Value) {
+				i = o[0]
+			}
+		}
+		last := len(ops) - 1
+		switch {
+		case i < 0:
+			set = append(set, entry)
+			if last >= 0 && ops[last].kind == patchInsert {
+				ops[last].count++
+			} else {
+				ops = append(ops, patchOp{kind: patchInsert, count: 1})
+			}
+		case last >= 0 && ops[last].kind == patchCopy && int(ops[last].start+ops[last].count) == i:
+			ops[last].count++
+		default:
+			ops = append(ops, patchOp{kind: patchCopy, start: uint32(i), count: 1})
+		}
+	}
+	var buf bytes.Buffer
+	bw := newBinaryWriter(&buf)
+	bw.Number(uint8(patchVersion))
+	bw.Number(uint32(len(ops)))
+	for _, op := range ops {
+		bw.Number(op.kind)
+		if op.kind == patchCopy {
+			bw.Number(op.start)
+		}
+		bw.Number(op.count)
+	}
+	bw.Add(set.WriteTo(&buf))
+	return buf.Bytes()
+}
+
+// ApplyPatch returns the result of applying a patch produced by MakePatch to
+// old. The result has the entries of the new model passed to MakePatch, in the
+// same order. Unchanged values are shared with old, which is not modified.
+//
+// Returns an error if the patch is malformed, or refers to entries beyond the
+// end of old.
+func ApplyPatch(old Model, patch []byte) (m Model, err error) {
+	r := bytes.NewReader(patch)
+	br := newBinaryReader(r)
+	var version uint8
+	if br.Number(&version) {
+		return m, fmt.Errorf("patch version: %w", br.Err())
+	}
+	if version != patchVersion {
+		return m, fmt.Errorf("patch version: unsupported version %d", version)
+	}
+	var length uint32
+	if br.Number(&length) {
+		return m, fmt.Errorf("patch ops length: %w", br.Err())
+	}
+	ops := make([]patchOp, 0, preallocLen(length))
+	for i := uint32(0); i < length; i++ {
+		var op patchOp
+		if br.Number(&op.kind) {
+			return m, fmt.Errorf("patch ops[%d]: %w", i, br.Err())
+		}
+		switch op.kind {
+		case patchCopy:
+			if br.Number(&op.start) {
+				return m, fmt.Errorf("patch ops[%d] start: %w", i, br.Err())
+			}
+		case patchInsert:
+		default:
+			return m, fmt.Errorf("patch ops[%d]: unknown kind %d", i, op.kind)
+		}
+		if br.Number(&op.count) {
+			return m, fmt.Errorf("patch ops[%d] count: %w", i, br.Err())
+		}
+		ops = append(ops, op)
+	}
+	var set ValueDictionary
+	if br.Add(set.ReadFrom(r)) {
+		return m, fmt.Errorf("patch entries: %w", br.Err())
+	}
+	if r.Len() > 0 {
+		return m, fmt.Errorf("patch: %d trailing bytes", r.Len())
+	}
+
+	var dict ValueDictionary
+	for i, op := range ops {
+		switch op.kind {
+		case patchCopy:
+			end := uint64(op.start) + uint64(op.count)
+			if end > uint64(len(old.Value)) {
+				return Model{}, fmt.Errorf("patch ops[%d]: copy of entries %d to %d exceeds length %d", i, op.start, end, len(old.Value))
+			}
+			dict = append(dict, old.Value[op.start:end]...)
+		case patchInsert:
+			if uint64(op.count) > uint64(len(set)) {
+				return Model{}, fmt.Errorf("patch ops[%d]: insert of %d entries exceeds remaining %d", i, op.count, len(set))
+			}
+			dict = append(dict, set[:op.count]...)
+			set = set[op.count:]
+		}
+	}
+	if len(set) > 0 {
+		return Model{}, fmt.Errorf("patch: %d entries not inserted", len(set))
+	}
+	m.Value = dict
+	return m, nil
+}
+
+// DiffText returns a human-readable description of the attributes that differ
+// between a and b, sorted by key. A removed attribute is described by a line
+// beginning with "-", and an added attribute by a line beginning with "+". A
+// changed attribute is described by both. Unchanged attributes are omitted.
+// Only the first entry of each key is considered.
+func DiffText(a, b Model) string {
+	changes := diff(a.Value, b.Value)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	var s strings.Builder
+	for _, c := range changes {
+		if c.Old != nil {
+			fmt.Fprintf(&s, "- %s = %s\n", c.Key, valueText(c.Old))
+		}
+		if c.New != nil {
+			fmt.Fprintf(&s, "+ %s = %s\n", c.Key, valueText(c.New))
+		}
+	}
+	return s.String()
+}
+
+// TypeDiff returns, for each key present in both a and b with values of
+// different types, the type of the value in a followed by the type of the value
+// in b. Only the first entry of each key is considered. Returns an empty map if
+// no types differ.
+func TypeDiff(a, b Model) map[string][2]Type {
+	types := map[string][2]Type{}
+	for _, c := range diff(a.Value, b.Value) {
+		if c.Old != nil && c.New != nil && c.Old.Type() != c.New.Type() {
+			types[c.Key] = [2]Type{c.Old.Type(), c.New.Type()}
+		}
+	}
+	return types
+}
+
+// valueText returns a description of v that includes its type and content.
+func valueText(v Value) string {
+	switch v := v.(type) {
+	case *ValueString:
+		return fmt.Sprintf("%s %q", v.Type(), string(*v))
+	case *ValueBytes:
+		return fmt.Sprintf("%s %q", v.Type(), []byte(*v))
+	case *ValueBool, *ValueFloat, *ValueFloatWide, *ValueDouble:
+		return fmt.Sprintf("%s %s", v.Type(), FormatValue(v))
+	}
+	return FormatValue(v)
+}
@@ -0,0 +1,193 @@
+package rbxattr_test
+
+import (
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestPatch(t *testing.T) {
+	name := rbxattr.ValueString("foo")
+	renamed := rbxattr.ValueString("bar")
+	enabled := rbxattr.ValueBool(true)
+	scale := rbxattr.ValueFloat(2)
+	old := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &name},
+		{Key: "Enabled", Value: &enabled},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+	}}
+	new := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &renamed},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{X: rbxattr.ValueUDim{Offset: 10}}},
+		{Key: "Scale", Value: &scale},
+	}}
+
+	patch := rbxattr.MakePatch(old, new)
+	got, err := rbxattr.ApplyPatch(old, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Value.Equal(new.Value) {
+		t.Fatal("patched model does not match new model")
+	}
+	if old.Value[0].Value != &name || len(old.Value) != 4 {
+		t.Fatal("old model was modified")
+	}
+	if size := int64(len(patch)); size >= new.Size() {
+		t.Errorf("expected patch (%d bytes) smaller than new model (%d bytes)", size, new.Size())
+	}
+
+	empty := rbxattr.MakePatch(old, old)
+	if got, err := rbxattr.ApplyPatch(old, empty); err != nil || !got.Value.Equal(old.Value) {
+		t.Fatalf("expected empty patch to preserve model, got error %v", err)
+	}
+
+	bad := append([]byte{}, patch...)
+	bad[0] = 2
+	if _, err := rbxattr.ApplyPatch(old, bad); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+	if _, err := rbxattr.ApplyPatch(old, patch[:len(patch)-1]); err == nil {
+		t.Error("expected error for truncated patch")
+	}
+	if _, err := rbxattr.ApplyPatch(old, append(patch, 0)); err == nil {
+		t.Error("expected error for trailing bytes")
+	}
+	if _, err := rbxattr.ApplyPatch(old, []byte{1, 0xFF, 0xFF, 0xFF, 0xFF}); err == nil {
+		t.Error("expected error for over-declared removed length")
+	}
+}
+
+func TestPatchOrder(t *testing.T) {
+	a := rbxattr.ValueString("a")
+	b := rbxattr.ValueString("b")
+	c := rbxattr.ValueString("c")
+	changed := rbxattr.ValueString("changed")
+	for _, test := range []struct {
+		name     string
+		old, new rbxattr.ValueDictionary
+	}{
+		{"insert", rbxattr.ValueDictionary{
+			{Key: "A", Value: &a},
+			{Key: "C", Value: &c},
+		}, rbxattr.ValueDictionary{
+			{Key: "A", Value: &a},
+			{Key: "B", Value: &b},
+			{Key: "C", Value: &c},
+		}},
+		{"reorder", rbxattr.ValueDictionary{
+			{Key: "A", Value: &a},
+			{Key: "B", Value: &b},
+			{Key: "C", Value: &c},
+		}, rbxattr.ValueDictionary{
+			{Key: "C", Value: &c},
+			{Key: "A", Value: &a},
+			{Key: "B", Value: &changed},
+		}},
+		{"duplicate", rbxattr.ValueDictionary{
+			{Key: "A", Value: &a},
+			{Key: "A", Value: &b},
+		}, rbxattr.ValueDictionary{
+			{Key: "A", Value: &changed},
+			{Key: "B", Value: &b},
+			{Key: "A", Value: &b},
+			{Key: "A", Value: &c},
+		}},
+		{"remove duplicate", rbxattr.ValueDictionary{
+			{Key: "A", Value: &a},
+			{Key: "A", Value: &b},
+			{Key: "C", Value: &c},
+		}, rbxattr.ValueDictionary{
+			{Key: "A", Value: &a},
+			{Key: "C", Value: &c},
+		}},
+	} {
+		old := rbxattr.Model{Value: test.old}
+		patch := rbxattr.MakePatch(old, rbxattr.Model{Value: test.new})
+		got, err := rbxattr.ApplyPatch(old, patch)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if !got.Value.Equal(test.new) {
+			t.Errorf("%s: patched model does not match new model", test.name)
+		}
+	}
+}
+
+func TestPatchOutOfRange(t *testing.T) {
+	old := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "A", Value: &rbxattr.ValueVector2{}},
+		{Key: "B", Value: &rbxattr.ValueVector2{}},
+	}}
+	patch := rbxattr.MakePatch(old, old)
+	if _, err := rbxattr.ApplyPatch(rbxattr.Model{Value: old.Value[:1]}, patch); err == nil {
+		t.Error("expected error for copy beyond end of old model")
+	}
+	for _, p := range [][]byte{
+		{1, 1, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 'A', 0x03, 1},
+	} {
+		if _, err := rbxattr.ApplyPatch(old, p); err == nil {
+			t.Errorf("%v: expected error", p)
+		}
+	}
+}
+
+func TestDiffText(t *testing.T) {
+	name := rbxattr.ValueString("foo")
+	renamed := rbxattr.ValueString("bar")
+	enabled := rbxattr.ValueBool(true)
+	scale := rbxattr.ValueFloat(2)
+	old := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &name},
+		{Key: "Enabled", Value: &enabled},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+	}}
+	new := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Scale", Value: &scale},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+		{Key: "Name", Value: &renamed},
+	}}
+	const want = `- Enabled = Bool true
+- Name = String "foo"
++ Name = String "bar"
++ Scale = Float 2
+`
+	if got := rbxattr.DiffText(old, new); got != want {
+		t.Errorf("expected diff\n%s\ngot\n%s", want, got)
+	}
+	if got := rbxattr.DiffText(old, old); got != "" {
+		t.Errorf("expected empty diff, got\n%s", got)
+	}
+}
+
+func TestTypeDiff(t *testing.T) {
+	name := rbxattr.ValueString("foo")
+	renamed := rbxattr.ValueString("bar")
+	f := rbxattr.ValueFloat(2)
+	d := rbxattr.ValueDouble(2)
+	old := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &name},
+		{Key: "Scale", Value: &f},
+		{Key: "Removed", Value: &f},
+	}}
+	new := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Scale", Value: &d},
+		{Key: "Name", Value: &renamed},
+		{Key: "Added", Value: &d},
+	}}
+	got := rbxattr.TypeDiff(old, new)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 changed type, got %v", got)
+	}
+	if want := [2]rbxattr.Type{rbxattr.TypeFloat, rbxattr.TypeDouble}; got["Scale"] != want {
+		t.Errorf("expected Scale to change from %v, got %v", want, got["Scale"])
+	}
+	if got := rbxattr.TypeDiff(old, old); len(got) != 0 {
+		t.Errorf("expected no changed types, got %v", got)
+	}
+}
@@ -0,0 +1,189 @@
+package rbxattr
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"reflect"
+)
+
+// Encoder encodes a Model to an output stream. In addition to encoding whole
+// models, an Encoder can stream the entries of a dictionary one at a time.
+type Encoder struct {
+	w io.Writer
+
+	// Trace, if not nil, receives a human-readable line for each field as it
+	// is encoded, including the offset at which the field was written, such
+	// as "wrote UDim2.X.Scale=0.5 at offset 13". Tracing does not affect the
+	// encoded bytes. Errors from Trace are ignored.
+	Trace io.Writer
+
+	// NormalizeNegativeZero causes each float component of a value that is
+	// negative zero to be encoded as positive zero, so that values that
+	// compare as equal numbers also encode to the same bytes. The values
+	// passed to the Encoder are not modified.
+	NormalizeNegativeZero bool
+
+	// AppendCRC causes a CRC-32 checksum (IEEE) of the encoded dictionary to
+	// be written as a uint32 after the dictionary ends. The checksum is not
+	// part of the attribute format, so the output can be decoded only by a
+	// Decoder with the VerifyCRC option.
+	AppendCRC bool
+
+	crc   hash.Hash32
+	n     int64
+	open  bool
+	count int
+	index int
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// flush flushes the underlying writer, if it supports flushing.
+func (e *Encoder) flush() error {
+	if f, ok := e.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Encode encodes m to the output stream.
+func (e *Encoder) Encode(m Model) error {
+	if err := e.BeginDictionary(len(m.Value)); err != nil {
+		return err
+	}
+	for _, entry := range m.Value {
+		if err := e.EncodeEntry(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+	return e.EndDictionary()
+}
+
+// BeginDictionary begins streaming a dictionary by writing its length prefix.
+// Exactly count entries must then be written with EncodeEntry before calling
+// EndDictionary.
+func (e *Encoder) BeginDictionary(count int) error {
+	if e.open {
+		return errors.New("format: dictionary already begun")
+	}
+	if count < 0 || uint64(count) > math.MaxUint32 {
+		return fmt.Errorf("format: invalid dictionary length %d", count)
+	}
+	e.crc = nil
+	if e.AppendCRC {
+		e.crc = crc32.NewIEEE()
+	}
+	bw := newBinaryWriter(e.dst())
+	if bw.Number(uint32(count)) {
+		e.n += bw.N()
+		return fmt.Errorf("format: Dictionary length: %w", bw.Err())
+	}
+	if e.Trace != nil {
+		t := tracer{w: e.Trace, offset: e.n}
+		t.field("Dictionary.Length", count, 4)
+	}
+	e.n += bw.N()
+	e.open = true
+	e.count = count
+	e.index = 0
+	return e.flush()
+}
+
+// EncodeEntry writes an entry of the dictionary begun by BeginDictionary, then
+// flushes the underlying writer if it has a Flush method.
+func (e *Encoder) EncodeEntry(key string, v Value) error {
+	if !e.open {
+		return errors.New("format: dictionary not begun")
+	}
+	if e.index >= e.count {
+		return fmt.Errorf("format: dictionary exceeds declared length %d", e.count)
+	}
+	if v == nil {
+		return fmt.Errorf("format: Dictionary[%d](%q) value: nil value", e.index, key)
+	}
+	if e.NormalizeNegativeZero {
+		v = normalizeNegativeZero(v)
+	}
+	entry := Entry{Key: key, Value: v}
+	n, err := entry.writeTo(e.dst(), e.index)
+	if err != nil {
+		e.n += n
+		return fmt.Errorf("format: %w", err)
+	}
+	if e.Trace != nil {
+		t := tracer{w: e.Trace, offset: e.n}
+		t.entry(e.index, entry)
+	}
+	e.n += n
+	e.index++
+	return e.flush()
+}
+
+// EndDictionary ends the dictionary begun by BeginDictionary. Returns an error
+// if fewer entries were written than were declared.
+func (e *Encoder) EndDictionary() error {
+	if !e.open {
+		return errors.New("format: dictionary not begun")
+	}
+	e.open = false
+	if e.index != e.count {
+		return fmt.Errorf("format: wrote %d of %d declared dictionary entries", e.index, e.count)
+	}
+	if e.crc == nil {
+		return nil
+	}
+	sum := e.crc.Sum32()
+	bw := newBinaryWriter(e.w)
+	if bw.Number(sum) {
+		e.n += bw.N()
+		return fmt.Errorf("format: CRC: %w", bw.Err())
+	}
+	if e.Trace != nil {
+		t := tracer{w: e.Trace, offset: e.n}
+		t.field("CRC", fmt.Sprintf("%08X", sum), 4)
+	}
+	e.n += bw.N()
+	return e.flush()
+}
+
+// dst returns the writer to which the dictionary is written.
+func (e *Encoder) dst() io.Writer {
+	if e.crc != nil {
+		return io.MultiWriter(e.w, e.crc)
+	}
+	return e.w
+}
+
+// normalizeNegativeZero returns v, or a copy of v in which each float
+// component that is negative zero is replaced by positive zero. v is returned
+// as is if it cannot be copied.
+func normalizeNegativeZero(v Value) Value {
+	negative := false
+	walkLeaves("", reflect.ValueOf(v), func(_ string, leaf reflect.Value) {
+		switch leaf.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f := leaf.Float()
+			negative = negative || f == 0 && math.Signbit(f)
+		}
+	})
+	if !negative || !canClone(reflect.ValueOf(v)) {
+		return v
+	}
+	v = cloneValue(v)
+	walkLeaves("", reflect.ValueOf(v), func(_ string, leaf reflect.Value) {
+		switch leaf.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if f := leaf.Float(); f == 0 && math.Signbit(f) && leaf.CanSet() {
+				leaf.SetFloat(0)
+			}
+		}
+	})
+	return v
+}
@@ -0,0 +1,259 @@
+package rbxattr_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestEncoderStream(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	f := rbxattr.ValueFloat(0.5)
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{
+			X: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+			Y: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+		}},
+		{Key: "Scale", Value: &f},
+	}}
+	var want bytes.Buffer
+	if _, err := model.WriteTo(&want); err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	bw := bufio.NewWriter(&got)
+	enc := rbxattr.NewEncoder(bw)
+	if err := enc.BeginDictionary(len(model.Value)); err != nil {
+		t.Fatal(err)
+	}
+	for i, entry := range model.Value {
+		if err := enc.EncodeEntry(entry.Key, entry.Value); err != nil {
+			t.Fatal(err)
+		}
+		if bw.Buffered() != 0 {
+			t.Fatalf("entry %d was not flushed", i)
+		}
+	}
+	if err := enc.EndDictionary(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("streamed bytes do not match\n\t%X\n\t%X", want.Bytes(), got.Bytes())
+	}
+
+	got.Reset()
+	if err := rbxattr.NewEncoder(&got).Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("encoded bytes do not match\n\t%X\n\t%X", want.Bytes(), got.Bytes())
+	}
+}
+
+func TestEncoderStreamCount(t *testing.T) {
+	b := rbxattr.ValueBool(true)
+	var buf bytes.Buffer
+
+	enc := rbxattr.NewEncoder(&buf)
+	if err := enc.EncodeEntry("A", &b); err == nil {
+		t.Error("expected error for entry before BeginDictionary")
+	}
+	if err := enc.BeginDictionary(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.EncodeEntry("A", &b); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.EncodeEntry("B", &b); err == nil {
+		t.Error("expected error for entry beyond declared count")
+	}
+	if err := enc.EndDictionary(); err != nil {
+		t.Fatal(err)
+	}
+
+	enc = rbxattr.NewEncoder(&buf)
+	if err := enc.BeginDictionary(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.EncodeEntry("A", &b); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.EndDictionary(); err == nil {
+		t.Error("expected error for too few entries")
+	}
+}
+
+func TestEncoderTrace(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/sample.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model rbxattr.Model
+	if _, err := model.ReadFrom(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, trace bytes.Buffer
+	enc := rbxattr.NewEncoder(&out)
+	enc.Trace = &trace
+	if err := enc.Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), b) {
+		t.Fatalf("traced bytes do not match\n\t%X\n\t%X", b, out.Bytes())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(trace.String(), "\n"), "\n")
+	if want := "wrote Dictionary.Length=14 at offset 0"; lines[0] != want {
+		t.Errorf("expected first line %q, got %q", want, lines[0])
+	}
+	for _, want := range []string{
+		`wrote Dictionary[0].Key="MMMM" at offset 4`,
+		"wrote Dictionary[0].Type=NumberRange at offset 12",
+		"wrote NumberRange.Min=-12 at offset 13",
+		"wrote NumberRange.Max=34 at offset 17",
+		"wrote NumberSequence[1].Time=0.21469575 at offset 50",
+	} {
+		if !strings.Contains(trace.String(), want+"\n") {
+			t.Errorf("missing trace line %q", want)
+		}
+	}
+
+	// Each traced key must be found at its reported offset.
+	keys := 0
+	for _, line := range lines {
+		var i int
+		var key string
+		var offset int
+		if n, _ := fmt.Sscanf(line, "wrote Dictionary[%d].Key=%q at offset %d", &i, &key, &offset); n != 3 {
+			continue
+		}
+		keys++
+		if offset+4 > len(b) || binary.LittleEndian.Uint32(b[offset:]) != uint32(len(key)) {
+			t.Errorf("Dictionary[%d]: key %q not at offset %d", i, key, offset)
+		}
+	}
+	if keys != len(model.Value) {
+		t.Errorf("expected %d traced keys, got %d", len(model.Value), keys)
+	}
+}
+
+func TestEncoderTraceOpaque(t *testing.T) {
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "A", Value: &opaqueValue{Scale: 1, offset: 2}},
+	}}
+	var out, trace bytes.Buffer
+	enc := rbxattr.NewEncoder(&out)
+	enc.Trace = &trace
+	if err := enc.Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"wrote Vector2.Scale=1 at offset 10",
+		"wrote Vector2.offset=2 at offset 14",
+	} {
+		if !strings.Contains(trace.String(), want+"\n") {
+			t.Errorf("missing trace line %q", want)
+		}
+	}
+}
+
+func TestEncoderNormalizeNegativeZero(t *testing.T) {
+	negZero := float32(math.Copysign(0, -1))
+	v := &rbxattr.ValueVector3{X: 1, Y: negZero, Z: 0}
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{{Key: "Position", Value: v}}}
+	positive := rbxattr.Model{Value: rbxattr.ValueDictionary{{Key: "Position", Value: &rbxattr.ValueVector3{X: 1}}}}
+
+	var raw, normalized, want bytes.Buffer
+	if err := rbxattr.NewEncoder(&raw).Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	enc := rbxattr.NewEncoder(&normalized)
+	enc.NormalizeNegativeZero = true
+	if err := enc.Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbxattr.NewEncoder(&want).Encode(positive); err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(raw.Bytes(), want.Bytes()) {
+		t.Error("expected negative zero to be preserved by default")
+	}
+	if !bytes.Equal(normalized.Bytes(), want.Bytes()) {
+		t.Errorf("expected normalized bytes\n\t%X\n\t%X", want.Bytes(), normalized.Bytes())
+	}
+	if !math.Signbit(float64(v.Y)) {
+		t.Error("encoded value was modified")
+	}
+	if model.Value.Equal(positive.Value) {
+		t.Error("expected Equal to distinguish negative zero")
+	}
+}
+
+func TestEncoderNormalizeNegativeZeroOpaque(t *testing.T) {
+	// A value that cannot be copied is encoded as is, rather than modified.
+	v := &opaqueValue{Scale: float32(math.Copysign(0, -1)), offset: 1}
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{{Key: "Opaque", Value: v}}}
+	var raw, normalized bytes.Buffer
+	if err := rbxattr.NewEncoder(&raw).Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	enc := rbxattr.NewEncoder(&normalized)
+	enc.NormalizeNegativeZero = true
+	if err := enc.Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw.Bytes(), normalized.Bytes()) {
+		t.Error("expected value that cannot be copied to be encoded as is")
+	}
+	if !math.Signbit(float64(v.Scale)) {
+		t.Error("encoded value was modified")
+	}
+}
+
+func TestEncoderAppendCRC(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+	}}
+	var buf bytes.Buffer
+	enc := rbxattr.NewEncoder(&buf)
+	enc.AppendCRC = true
+	if err := enc.Encode(model); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if n := int64(len(data)); n != model.Size()+4 {
+		t.Fatalf("expected %d bytes, got %d", model.Size()+4, n)
+	}
+
+	dec := rbxattr.NewDecoder(bytes.NewReader(data))
+	dec.VerifyCRC = true
+	got, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Value.Equal(model.Value) {
+		t.Fatal("decoded model does not match")
+	}
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-8] ^= 0x01
+	dec = rbxattr.NewDecoder(bytes.NewReader(corrupt))
+	dec.VerifyCRC = true
+	if _, err := dec.Decode(); !errors.Is(err, rbxattr.ErrChecksum) {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+}
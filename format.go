@@ -5,6 +5,7 @@ package rbxattr
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 )
 
 // Model is a low-level model of Roblox's instance attribute format.
@@ -12,7 +13,8 @@ type Model struct {
 	Value ValueDictionary
 }
 
-// ReadFrom decodes bytes from r, setting Value on success.
+// ReadFrom decodes bytes from r, setting Value on success. An empty dictionary
+// decodes to an empty, non-nil ValueDictionary.
 func (f *Model) ReadFrom(r io.Reader) (n int64, err error) {
 	n, err = f.Value.ReadFrom(r)
 	if err != nil {
@@ -22,6 +24,14 @@ func (f *Model) ReadFrom(r io.Reader) (n int64, err error) {
 }
 
 // WriteTo encodes Value into bytes written to w.
+//
+// Encoding a decoded model reproduces the decoded bytes exactly, including
+// NaN payloads and denormal floats, provided that they are in the canonical
+// form that Roblox produces. Other forms are written in canonical form: a Bool
+// byte other than 0 is written as 1, a CFrame whose explicit rotation matrix
+// is one of the special orientations is written with the ID of that
+// orientation, and a CFrame with an unknown ID is written with the ID of the
+// identity orientation.
 func (f *Model) WriteTo(w io.Writer) (n int64, err error) {
 	n, err = f.Value.WriteTo(w)
 	if err != nil {
@@ -29,3 +39,61 @@ func (f *Model) WriteTo(w io.Writer) (n int64, err error) {
 	}
 	return n, err
 }
+
+// Size returns the number of bytes that WriteTo would write, which includes
+// the length prefix of the dictionary, and the key, type, and value of each
+// entry. That is, Size is the full encoded size of the model, and not only the
+// size of its values.
+func (f Model) Size() int64 {
+	n, _ := f.Value.WriteTo(ioutil.Discard)
+	return n
+}
+
+// LargestEntry returns the key of the entry whose value has the largest
+// encoded size, along with that size. The size of a value includes the content
+// of any strings and sequences within it, but not the key or type of the
+// entry. If several entries are equally large, the first is returned. Returns
+// an empty key and a size of 0 if the model is empty.
+func (f Model) LargestEntry() (key string, size int64) {
+	size = -1
+	for _, entry := range f.Value {
+		if n, _ := entry.Value.WriteTo(ioutil.Discard); n > size {
+			key, size = entry.Key, n
+		}
+	}
+	if size < 0 {
+		return "", 0
+	}
+	return key, size
+}
+
+// Chunk partitions the entries of f into consecutive models that each encode
+// to at most maxBytes, as reported by Size. Entries retain their order, and
+// each model contains as many entries as fit. Returns an error if a single
+// entry does not fit within maxBytes. An empty model produces no chunks.
+func (f Model) Chunk(maxBytes int64) ([]Model, error) {
+	const prefix = 4 // Length of dictionary.
+	var chunks []Model
+	var chunk ValueDictionary
+	size := int64(prefix)
+	for i, entry := range f.Value {
+		n, err := entry.writeTo(ioutil.Discard, i)
+		if err != nil {
+			return nil, fmt.Errorf("format: %w", err)
+		}
+		if prefix+n > maxBytes {
+			return nil, fmt.Errorf("format: Dictionary[%d](%q): size %d exceeds chunk limit %d", i, entry.Key, prefix+n, maxBytes)
+		}
+		if size+n > maxBytes {
+			chunks = append(chunks, Model{Value: chunk})
+			chunk = nil
+			size = prefix
+		}
+		chunk = append(chunk, entry)
+		size += n
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, Model{Value: chunk})
+	}
+	return chunks, nil
+}
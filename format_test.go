@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -38,6 +40,95 @@ func TestModelRoundtrip(t *testing.T) {
 	}
 }
 
+func TestModelEmpty(t *testing.T) {
+	var empty rbxattr.Model
+	if size := empty.Size(); size != 4 {
+		t.Errorf("expected size 4, got %d", size)
+	}
+	var buf bytes.Buffer
+	if n, err := empty.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	} else if n != 4 || !bytes.Equal(buf.Bytes(), []byte{0, 0, 0, 0}) {
+		t.Fatalf("expected 4 zero bytes, got %d bytes %X", n, buf.Bytes())
+	}
+
+	var decoded rbxattr.Model
+	if n, err := decoded.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	} else if n != 4 {
+		t.Fatalf("expected 4 bytes read, got %d", n)
+	}
+	if decoded.Value == nil || len(decoded.Value) != 0 {
+		t.Fatalf("expected empty non-nil dictionary, got %#v", decoded.Value)
+	}
+	if !decoded.Value.Equal(empty.Value) {
+		t.Error("decoded model does not equal empty model")
+	}
+}
+
+func TestModelCorpusAllTypes(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/corpus/all_types.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model rbxattr.Model
+	if _, err := model.ReadFrom(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	found := map[rbxattr.Type]bool{}
+	for _, entry := range model.Value {
+		found[entry.Value.Type()] = true
+	}
+	for name, typ := range exportedTypes {
+		if !found[typ] {
+			t.Errorf("%s: no entry in all_types.bin", name)
+		}
+	}
+}
+
+func TestModelCorpus(t *testing.T) {
+	const dir = "testdata/corpus"
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("empty corpus")
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		name := file.Name()
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var model rbxattr.Model
+			n, err := model.ReadFrom(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(data)) {
+				t.Fatalf("expected %d bytes read, got %d", len(data), n)
+			}
+			var w bytes.Buffer
+			n, err = model.WriteTo(&w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(w.Bytes(), data) {
+				t.Fatal("encoded bytes do not match decoded bytes")
+			}
+			// Size includes the dictionary length, keys, and types.
+			if size := model.Size(); size != n || size != int64(len(data)) {
+				t.Fatalf("expected size %d, got %d", n, size)
+			}
+		})
+	}
+}
+
 func ExampleModel_ReadFrom() {
 	var data = `AgAAAAQAAABTaXplCgAAAD9kAAAAAAAAP2QAAAAIAAAAUG9zaXRpb24KAACAPs7///8AAIA+zv///w==`
 	r := base64.NewDecoder(base64.StdEncoding, strings.NewReader(data))
@@ -98,3 +189,63 @@ func ExampleModel_WriteTo() {
 	// Wrote 58 bytes
 	// AgAAAAQAAABTaXplCgAAAD9kAAAAAAAAP2QAAAAIAAAAUG9zaXRpb24KAACAPs7///8AAIA+zv///w==
 }
+
+func TestModelChunk(t *testing.T) {
+	b := rbxattr.ValueBool(true)
+	var model rbxattr.Model
+	for _, key := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		// Each entry encodes to 7 bytes.
+		model.Value = append(model.Value, rbxattr.Entry{Key: key, Value: &b})
+	}
+
+	chunks, err := model.Chunk(4 + 7*3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	var joined rbxattr.ValueDictionary
+	for i, chunk := range chunks {
+		if size := chunk.Size(); size > 4+7*3 {
+			t.Errorf("chunk %d: size %d exceeds limit", i, size)
+		}
+		joined = append(joined, chunk.Value...)
+	}
+	if n := len(chunks[2].Value); n != 1 {
+		t.Errorf("expected 1 entry in last chunk, got %d", n)
+	}
+	if !joined.Equal(model.Value) {
+		t.Error("chunks do not contain the original entries")
+	}
+
+	if _, err := model.Chunk(4 + 6); err == nil {
+		t.Error("expected error for entry exceeding limit")
+	}
+	if chunks, err := (rbxattr.Model{}).Chunk(4); err != nil || len(chunks) != 0 {
+		t.Errorf("expected no chunks for empty model, got %d, %v", len(chunks), err)
+	}
+}
+
+func TestModelLargestEntry(t *testing.T) {
+	short := rbxattr.ValueString("foo")
+	long := rbxattr.ValueString(strings.Repeat("x", 1000))
+	seq := make(rbxattr.ValueNumberSequence, 10)
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &short},
+		{Key: "Description", Value: &long},
+		{Key: "Curve", Value: &seq},
+		{Key: "Position", Value: &rbxattr.ValueVector3{}},
+	}}
+	key, size := model.LargestEntry()
+	if key != "Description" || size != 4+1000 {
+		t.Errorf("expected Description with size %d, got %s with size %d", 4+1000, key, size)
+	}
+	model.Value = model.Value[2:]
+	if key, size := model.LargestEntry(); key != "Curve" || size != 4+12*10 {
+		t.Errorf("expected Curve with size %d, got %s with size %d", 4+12*10, key, size)
+	}
+	if key, size := (rbxattr.Model{}).LargestEntry(); key != "" || size != 0 {
+		t.Errorf("expected no entry, got %s with size %d", key, size)
+	}
+}
@@ -0,0 +1,48 @@
+package rbxattr
+
+// FrozenModel is an immutable view of a Model. It is safe for concurrent use
+// by multiple goroutines. Values obtained from a FrozenModel are copies, so
+// modifying them does not affect the FrozenModel.
+type FrozenModel struct {
+	value ValueDictionary
+	index map[string]int
+}
+
+// Freeze returns an immutable copy of f. Modifying f afterwards does not affect
+// the returned FrozenModel. As with Clone, a value of a type with unexported
+// fields cannot be copied, and is shared with f instead.
+func (f Model) Freeze() FrozenModel {
+	v := f.Value.Clone()
+	return FrozenModel{value: v, index: v.Index()}
+}
+
+// Len returns the number of entries in the model, including entries with a
+// duplicate key.
+func (f FrozenModel) Len() int {
+	return len(f.value)
+}
+
+// Get returns a copy of the value of the first entry with the given key.
+// Returns false if no entry has the key.
+func (f FrozenModel) Get(key string) (Value, bool) {
+	v, ok := f.value.GetIndexed(f.index, key)
+	if !ok {
+		return nil, false
+	}
+	return cloneValue(v), true
+}
+
+// Walk calls fn with the key and a copy of the value of each entry in order,
+// stopping early if fn returns false.
+func (f FrozenModel) Walk(fn func(key string, v Value) bool) {
+	for _, entry := range f.value {
+		if !fn(entry.Key, cloneValue(entry.Value)) {
+			return
+		}
+	}
+}
+
+// Model returns a mutable copy of the model.
+func (f FrozenModel) Model() Model {
+	return Model{Value: f.value.Clone()}
+}
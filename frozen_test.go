@@ -0,0 +1,97 @@
+package rbxattr_test
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestFrozenModel(t *testing.T) {
+	name := rbxattr.ValueString("foo")
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &name},
+		{Key: "Sequence", Value: &rbxattr.ValueNumberSequence{{Time: 0}, {Time: 1}}},
+	}}
+	frozen := model.Freeze()
+	name = "bar"
+	model.Value[1].Key = "Changed"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if n := frozen.Len(); n != 2 {
+				t.Errorf("expected length 2, got %d", n)
+			}
+			v, ok := frozen.Get("Name")
+			if !ok || *v.(*rbxattr.ValueString) != "foo" {
+				t.Errorf("expected Name foo, got %v", v)
+			}
+			seq, ok := frozen.Get("Sequence")
+			if !ok {
+				t.Error("expected Sequence")
+				return
+			}
+			// Modifying a copy must not race with other readers.
+			(*seq.(*rbxattr.ValueNumberSequence))[1].Time = 2
+			count := 0
+			frozen.Walk(func(key string, v rbxattr.Value) bool {
+				count++
+				*v.(*rbxattr.ValueString) = "baz"
+				return false
+			})
+			if count != 1 {
+				t.Errorf("expected Walk to stop after 1 entry, visited %d", count)
+			}
+		}()
+	}
+	wg.Wait()
+
+	m := frozen.Model()
+	if v, _ := m.Value.Get("Name"); *v.(*rbxattr.ValueString) != "foo" {
+		t.Errorf("expected Name foo, got %v", v)
+	}
+	if v, _ := m.Value.Get("Sequence"); (*v.(*rbxattr.ValueNumberSequence))[1].Time != 1 {
+		t.Error("frozen model was modified")
+	}
+}
+
+// opaqueValue is a Value implemented outside of the package, with an
+// unexported field that cannot be accessed by reflection.
+type opaqueValue struct {
+	Scale  float32
+	offset float32
+}
+
+func (*opaqueValue) Type() rbxattr.Type {
+	return rbxattr.TypeVector2
+}
+
+func (v *opaqueValue) ReadFrom(r io.Reader) (n int64, err error) {
+	var u rbxattr.ValueVector2
+	n, err = u.ReadFrom(r)
+	v.Scale, v.offset = u.X, u.Y
+	return n, err
+}
+
+func (v *opaqueValue) WriteTo(w io.Writer) (n int64, err error) {
+	return rbxattr.ValueVector2{X: v.Scale, Y: v.offset}.WriteTo(w)
+}
+
+func TestFrozenModelOpaque(t *testing.T) {
+	v := &opaqueValue{Scale: 1, offset: 2}
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{{Key: "Opaque", Value: v}}}
+	frozen := model.Freeze()
+	if got, ok := frozen.Get("Opaque"); !ok || got != v {
+		t.Errorf("expected value that cannot be copied to be shared, got %v", got)
+	}
+	frozen.Walk(func(key string, got rbxattr.Value) bool {
+		if got != v {
+			t.Errorf("%s: expected shared value, got %v", key, got)
+		}
+		return true
+	})
+}
@@ -0,0 +1,92 @@
+package rbxattr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// hexRegion is a labeled range of bytes within an encoded model.
+type hexRegion struct {
+	offset int64
+	size   int64
+	note   string
+}
+
+// HexDump decodes the model encoded in b, and writes to w an annotated hex
+// view of its bytes, in the manner of "hexdump -C". Each region of the
+// encoding, being the length prefix of the dictionary, and the key, type, and
+// value of each entry, begins on a new line, and is labeled on its first line.
+// Bytes following the dictionary are labeled as trailing data.
+//
+// Nothing is written if b cannot be decoded.
+func HexDump(b []byte, w io.Writer) error {
+	r := bytes.NewReader(b)
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return fmt.Errorf("format: Dictionary length: %w", br.Err())
+	}
+	regions := []hexRegion{{0, 4, fmt.Sprintf("length prefix (%d entries)", length)}}
+	offset := int64(4)
+	for i := 0; i < int(length); i++ {
+		var e Entry
+		n, err := e.readFrom(r, i, NewValue, nil)
+		if err != nil {
+			return fmt.Errorf("format: %w", err)
+		}
+		k := 4 + int64(len(e.Key))
+		regions = append(regions,
+			hexRegion{offset, k, fmt.Sprintf("Dictionary[%d] key %q", i, e.Key)},
+			hexRegion{offset + k, 1, fmt.Sprintf("Dictionary[%d] type %s", i, e.Value.Type())},
+			hexRegion{offset + k + 1, n - k - 1, fmt.Sprintf("Dictionary[%d] value %s", i, FormatValue(e.Value))},
+		)
+		offset += n
+	}
+	if rest := int64(len(b)) - offset; rest > 0 {
+		regions = append(regions, hexRegion{offset, rest, "trailing data"})
+	}
+
+	var buf bytes.Buffer
+	for _, region := range regions {
+		note := region.note
+		for i := int64(0); i < region.size || i == 0; i += 16 {
+			end := i + 16
+			if end > region.size {
+				end = region.size
+			}
+			writeHexLine(&buf, region.offset+i, b[region.offset+i:region.offset+end], note)
+			note = ""
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeHexLine writes a line of up to 16 bytes p, starting at offset, followed
+// by note.
+func writeHexLine(buf *bytes.Buffer, offset int64, p []byte, note string) {
+	fmt.Fprintf(buf, "%08x ", offset)
+	for i := 0; i < 16; i++ {
+		if i == 8 {
+			buf.WriteByte(' ')
+		}
+		if i < len(p) {
+			fmt.Fprintf(buf, " %02x", p[i])
+		} else {
+			buf.WriteString("   ")
+		}
+	}
+	buf.WriteString("  |")
+	for _, c := range p {
+		if c < 0x20 || c > 0x7E {
+			c = '.'
+		}
+		buf.WriteByte(c)
+	}
+	buf.WriteString("|")
+	if note != "" {
+		fmt.Fprintf(buf, "%*s%s", 18-len(p), "", note)
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,60 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestHexDump(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/sample.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ioutil.ReadFile("testdata/sample.hexdump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := rbxattr.HexDump(b, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("unexpected dump:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHexDumpTrailing(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/empty.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := rbxattr.HexDump(append(b, 0xAB), &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"00000000  00 00 00 00                                       |....|              length prefix (0 entries)\n" +
+		"00000004  ab                                                |.|                 trailing data\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected dump:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHexDumpInvalid(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/sample.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = rbxattr.HexDump(b[:len(b)-1], &buf)
+	if err == nil || !strings.Contains(err.Error(), `Dictionary[13]("AAAA") value`) {
+		t.Errorf("expected error in last value, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %d bytes", buf.Len())
+	}
+}
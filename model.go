@@ -0,0 +1,104 @@
+package rbxattr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Signature returns a compact summary of the number of entries of each type
+// in the model, such as "Bool:2,String:1,UDim2:2". Types are listed in order
+// of name. Models with the same distribution of types have the same
+// signature, regardless of keys, values, or order.
+func (f Model) Signature() string {
+	counts := map[string]int{}
+	for _, entry := range f.Value {
+		counts[entry.Value.Type().String()]++
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s:%d", name, counts[name])
+	}
+	return b.String()
+}
+
+// TypeSet returns the set of types of the values in the model, including
+// values of entries with a duplicate key. Unlike Signature, only the presence
+// of each type is reported. A decoder must support each of these types to read
+// the model.
+func (f Model) TypeSet() map[Type]bool {
+	types := map[Type]bool{}
+	for _, entry := range f.Value {
+		types[entry.Value.Type()] = true
+	}
+	return types
+}
+
+// Schema returns a map of each key in the model to the name of the type of its
+// value, such as "UDim2". Only the first entry of each key is considered.
+// Unlike Signature, Schema describes the shape of the attributes by name, which
+// is useful for comparing the attributes produced and expected by different
+// programs.
+func (f Model) Schema() map[string]string {
+	schema := make(map[string]string, len(f.Value))
+	for _, entry := range f.Value {
+		if _, ok := schema[entry.Key]; !ok {
+			schema[entry.Key] = entry.Value.Type().String()
+		}
+	}
+	return schema
+}
+
+// Subset returns a new model containing only the entries of the model with one
+// of the given keys, in their original order. Only the first entry of each key
+// is included. Keys not present in the model are ignored. Values are shared
+// with the model rather than copied.
+func (f Model) Subset(keys ...string) Model {
+	want := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		want[key] = true
+	}
+	d := make(ValueDictionary, 0, len(want))
+	for _, entry := range f.Value {
+		if want[entry.Key] {
+			d = append(d, entry)
+			want[entry.Key] = false
+		}
+	}
+	return Model{Value: d}
+}
+
+// Reduce combines models into a single model. Entries are added in order of
+// the first appearance of their key. When a key appears in more than one
+// model, the value is the result of combine, which receives the key, the value
+// accumulated so far, and the value from the later model. Within each model,
+// only the first entry of each key is considered. The models are not modified,
+// and values are shared with them except where replaced by combine.
+func Reduce(models []Model, combine func(key string, a, b Value) Value) Model {
+	var d ValueDictionary
+	idx := map[string]int{}
+	for _, m := range models {
+		seen := make(map[string]bool, len(m.Value))
+		for _, entry := range m.Value {
+			if seen[entry.Key] {
+				continue
+			}
+			seen[entry.Key] = true
+			if i, ok := idx[entry.Key]; ok {
+				d[i].Value = combine(entry.Key, d[i].Value, entry.Value)
+				continue
+			}
+			idx[entry.Key] = len(d)
+			d = append(d, entry)
+		}
+	}
+	return Model{Value: d}
+}
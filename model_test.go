@@ -0,0 +1,184 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestModelSignature(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	b := rbxattr.ValueBool(true)
+	a := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Enabled", Value: &b},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+		{Key: "Name", Value: &s},
+		{Key: "Visible", Value: &b},
+		{Key: "Position", Value: &rbxattr.ValueUDim2{}},
+	}}
+	const want = "Bool:2,String:1,UDim2:2"
+	if got := a.Signature(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	other := rbxattr.ValueString("bar")
+	c := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "A", Value: &rbxattr.ValueUDim2{X: rbxattr.ValueUDim{Scale: 1}}},
+		{Key: "B", Value: &other},
+		{Key: "C", Value: &b},
+		{Key: "D", Value: &rbxattr.ValueUDim2{}},
+		{Key: "E", Value: &b},
+	}}
+	if got := c.Signature(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := (rbxattr.Model{}).Signature(); got != "" {
+		t.Fatalf("expected empty signature, got %q", got)
+	}
+}
+
+func TestModelTypeSet(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	b := rbxattr.ValueBool(true)
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Enabled", Value: &b},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+		{Key: "Name", Value: &s},
+		{Key: "Name", Value: &rbxattr.ValueColorSequence{}},
+		{Key: "Visible", Value: &b},
+	}}
+	got := model.TypeSet()
+	want := []rbxattr.Type{rbxattr.TypeBool, rbxattr.TypeUDim2, rbxattr.TypeString, rbxattr.TypeColorSequence}
+	if len(got) != len(want) {
+		t.Errorf("expected %d types, got %v", len(want), got)
+	}
+	for _, typ := range want {
+		if !got[typ] {
+			t.Errorf("expected type %s in set", typ)
+		}
+	}
+	if got := (rbxattr.Model{}).TypeSet(); len(got) != 0 {
+		t.Errorf("expected empty set, got %v", got)
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	for typ, want := range map[rbxattr.Type]string{
+		rbxattr.TypeString: "String",
+		rbxattr.TypeUDim2:  "UDim2",
+		rbxattr.TypeRect:   "Rect",
+		0x04:               "Int",
+		0x16:               "0x16",
+		0xFF:               "0xFF",
+	} {
+		if got := typ.String(); got != want {
+			t.Errorf("type 0x%02X: expected %q, got %q", byte(typ), want, got)
+		}
+	}
+}
+
+func TestModelSubset(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	b := rbxattr.ValueBool(true)
+	d := rbxattr.ValueDouble(1)
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Enabled", Value: &b},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+		{Key: "Name", Value: &s},
+		{Key: "Size", Value: &d},
+		{Key: "Speed", Value: &d},
+	}}
+	got := model.Subset("Name", "Size", "Missing")
+	want := rbxattr.ValueDictionary{
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+		{Key: "Name", Value: &s},
+	}
+	if !got.Value.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got.Value)
+	}
+	if got := model.Subset(); len(got.Value) != 0 {
+		t.Fatalf("expected empty subset, got %v", got.Value)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	double := func(f float64) *rbxattr.ValueDouble {
+		v := rbxattr.ValueDouble(f)
+		return &v
+	}
+	float := rbxattr.ValueFloat(0.5)
+	name := rbxattr.ValueString("foo")
+	models := []rbxattr.Model{
+		{Value: rbxattr.ValueDictionary{
+			{Key: "Score", Value: double(1)},
+			{Key: "Name", Value: &name},
+		}},
+		{Value: rbxattr.ValueDictionary{
+			{Key: "Bonus", Value: double(10)},
+			{Key: "Score", Value: double(2)},
+			{Key: "Score", Value: double(1000)},
+		}},
+		{Value: rbxattr.ValueDictionary{
+			{Key: "Score", Value: &float},
+			{Key: "Bonus", Value: double(20)},
+		}},
+	}
+	sum := func(key string, a, b rbxattr.Value) rbxattr.Value {
+		x, err := rbxattr.Coerce(a, rbxattr.TypeDouble)
+		if err != nil {
+			return a
+		}
+		y, err := rbxattr.Coerce(b, rbxattr.TypeDouble)
+		if err != nil {
+			return a
+		}
+		return double(float64(*x.(*rbxattr.ValueDouble)) + float64(*y.(*rbxattr.ValueDouble)))
+	}
+	got := rbxattr.Reduce(models, sum)
+	want := rbxattr.ValueDictionary{
+		{Key: "Score", Value: double(3.5)},
+		{Key: "Name", Value: &name},
+		{Key: "Bonus", Value: double(30)},
+	}
+	if !got.Value.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got.Value)
+	}
+	if v, _ := models[0].Value.Get("Score"); *v.(*rbxattr.ValueDouble) != 1 {
+		t.Error("original model was modified")
+	}
+	if got := rbxattr.Reduce(nil, sum); len(got.Value) != 0 {
+		t.Errorf("expected empty model, got %v", got.Value)
+	}
+}
+
+func TestModelSchema(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/udim2.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model rbxattr.Model
+	if _, err := model.ReadFrom(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+	s := rbxattr.ValueString("foo")
+	model.Value = append(model.Value,
+		rbxattr.Entry{Key: "Name", Value: &s},
+		rbxattr.Entry{Key: "Size", Value: &s},
+	)
+	want := map[string]string{
+		"Size":     "UDim2",
+		"Position": "UDim2",
+		"Name":     "String",
+	}
+	got := model.Schema()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for key, typ := range want {
+		if got[key] != typ {
+			t.Errorf("%s: expected %s, got %s", key, typ, got[key])
+		}
+	}
+}
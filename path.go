@@ -0,0 +1,347 @@
+package rbxattr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathElem is one element of a path. An element either selects a named field,
+// or an index when name is empty.
+type pathElem struct {
+	name  string
+	index int
+}
+
+// parsePath parses a path such as "Size.X.Scale" or "Sequence[2].Value" into
+// its elements. The first element is always the name of an attribute.
+func parsePath(path string) (elems []pathElem, err error) {
+	if path == "" {
+		return nil, errors.New("empty path")
+	}
+	for i, segment := range strings.Split(path, ".") {
+		name := segment
+		if j := strings.IndexByte(segment, '['); j >= 0 {
+			name = segment[:j]
+			segment = segment[j:]
+		} else {
+			segment = ""
+		}
+		if name == "" && (i > 0 || segment == "") {
+			return nil, fmt.Errorf("path %q: empty field name", path)
+		}
+		if name != "" {
+			elems = append(elems, pathElem{name: name})
+		}
+		for segment != "" {
+			j := strings.IndexByte(segment, ']')
+			if segment[0] != '[' || j < 0 {
+				return nil, fmt.Errorf("path %q: malformed index", path)
+			}
+			index, err := strconv.Atoi(segment[1:j])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q: invalid index %q", path, segment[1:j])
+			}
+			elems = append(elems, pathElem{index: index})
+			segment = segment[j+1:]
+		}
+	}
+	if elems[0].name == "" {
+		return nil, fmt.Errorf("path %q: must begin with an attribute name", path)
+	}
+	return elems, nil
+}
+
+// resolvePath returns the value addressed by path. The first element of the
+// path selects the first entry of f with a matching key. Remaining elements
+// select fields of structs, and elements of arrays and slices.
+func (f Model) resolvePath(path string) (rv reflect.Value, err error) {
+	elems, err := parsePath(path)
+	if err != nil {
+		return rv, err
+	}
+	for _, entry := range f.Value {
+		if entry.Key == elems[0].name {
+			rv = reflect.ValueOf(entry.Value)
+			break
+		}
+	}
+	if !rv.IsValid() {
+		return rv, fmt.Errorf("path %q: unknown attribute %q", path, elems[0].name)
+	}
+	for _, elem := range elems[1:] {
+		for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+			if rv.IsNil() {
+				return rv, fmt.Errorf("path %q: nil value", path)
+			}
+			rv = rv.Elem()
+		}
+		if elem.name != "" {
+			if rv.Kind() != reflect.Struct {
+				return rv, fmt.Errorf("path %q: %s has no field %q", path, rv.Type(), elem.name)
+			}
+			field, ok := rv.Type().FieldByName(elem.name)
+			if !ok || field.PkgPath != "" {
+				return rv, fmt.Errorf("path %q: %s has no field %q", path, rv.Type(), elem.name)
+			}
+			rv = rv.FieldByIndex(field.Index)
+			continue
+		}
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return rv, fmt.Errorf("path %q: %s cannot be indexed", path, rv.Type())
+		}
+		if elem.index >= rv.Len() {
+			return rv, fmt.Errorf("path %q: index %d out of range", path, elem.index)
+		}
+		rv = rv.Index(elem.index)
+	}
+	return rv, nil
+}
+
+// GetPath returns the value addressed by path, which is an attribute name
+// optionally followed by dotted field names and bracketed indices, such as
+// "Size.X.Scale" or "Sequence[2].Value". If the path refers to a single
+// attribute, then the Value of the attribute is returned. Otherwise, a copy of
+// the addressed field is returned. Returns false if path could not be
+// resolved.
+func (f Model) GetPath(path string) (interface{}, bool) {
+	rv, err := f.resolvePath(path)
+	if err != nil {
+		return nil, false
+	}
+	return rv.Interface(), true
+}
+
+// SetPath sets the value addressed by path to v. The path has the same form as
+// in GetPath. A path that refers to a single attribute sets the content of the
+// existing Value. v must have the same type as the addressed value, or a type
+// of the same kind that is convertible to it. Alternatively, when both are
+// numbers, v may be of any numeric kind, such as an untyped integer constant
+// for a float32 or int32 field, as long as v is within the range of the field.
+// A float assigned to an integer field must be a whole number, while a float
+// assigned to a float32 field is rounded to the nearest float32.
+func (f *Model) SetPath(path string, v interface{}) error {
+	rv, err := f.resolvePath(path)
+	if err != nil {
+		return err
+	}
+	if rv.Kind() == reflect.Ptr && !rv.CanSet() {
+		if rv.IsNil() {
+			return fmt.Errorf("path %q: nil value", path)
+		}
+		rv = rv.Elem()
+	}
+	if !rv.CanSet() {
+		return fmt.Errorf("path %q: value cannot be set", path)
+	}
+	nv := reflect.ValueOf(v)
+	if !nv.IsValid() {
+		return fmt.Errorf("path %q: cannot set %s to nil", path, rv.Type())
+	}
+	if nv.Type() != rv.Type() {
+		if isNumber(nv.Kind()) && isNumber(rv.Kind()) {
+			if !convertNumber(nv, rv) {
+				return fmt.Errorf("path %q: cannot set %s to %v (%s): out of range", path, rv.Type(), v, nv.Type())
+			}
+			return nil
+		}
+		if nv.Kind() != rv.Kind() || !nv.Type().ConvertibleTo(rv.Type()) {
+			return fmt.Errorf("path %q: cannot set %s to %s", path, rv.Type(), nv.Type())
+		}
+		nv = nv.Convert(rv.Type())
+	}
+	rv.Set(nv)
+	return nil
+}
+
+// isNumber returns whether k is an integer or float kind.
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertNumber sets dst to the number src, which may be of another numeric
+// kind. Returns false without setting dst if src is not within the range of
+// dst, or if src is a float that is not a whole number and dst is an integer.
+func convertNumber(src, dst reflect.Value) bool {
+	var f float64
+	var i int64
+	var u uint64
+	var neg bool
+	switch src.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f = src.Float()
+		switch dst.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if dst.OverflowFloat(f) && !math.IsInf(f, 0) {
+				return false
+			}
+			dst.SetFloat(f)
+			return true
+		}
+		if f != math.Trunc(f) || math.IsInf(f, 0) || f < math.MinInt64 || f >= math.MaxUint64 {
+			return false
+		}
+		if neg = f < 0; neg {
+			i = int64(f)
+		} else {
+			u = uint64(f)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = src.Int()
+		if neg = i < 0; !neg {
+			u = uint64(i)
+		}
+	default:
+		u = src.Uint()
+	}
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if neg {
+			dst.SetFloat(float64(i))
+		} else {
+			dst.SetFloat(float64(u))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !neg {
+			if u > math.MaxInt64 {
+				return false
+			}
+			i = int64(u)
+		}
+		if dst.OverflowInt(i) {
+			return false
+		}
+		dst.SetInt(i)
+	default:
+		if neg || dst.OverflowUint(u) {
+			return false
+		}
+		dst.SetUint(u)
+	}
+	return true
+}
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// walkLeaves calls fn with each leaf of rv and the path to the leaf, where
+// path is the path to rv. Structs, arrays, and slices are traversed, while
+// byte slices and all other values are leaves.
+func walkLeaves(path string, rv reflect.Value, fn func(path string, leaf reflect.Value)) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" {
+				walkLeaves(path+"."+t.Field(i).Name, rv.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().ConvertibleTo(bytesType) {
+			fn(path, rv)
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			walkLeaves(path+"["+strconv.Itoa(i)+"]", rv.Index(i), fn)
+		}
+	default:
+		fn(path, rv)
+	}
+}
+
+// walkModelLeaves calls walkLeaves for the first entry of each key in f.
+func (f Model) walkModelLeaves(fn func(path string, leaf reflect.Value)) {
+	idx := f.Value.Index()
+	for i, entry := range f.Value {
+		if idx[entry.Key] == i {
+			walkLeaves(entry.Key, reflect.ValueOf(entry.Value), fn)
+		}
+	}
+}
+
+// Strings returns the content of each string within the model, mapped by the
+// path to the string, as accepted by GetPath. This includes String values, and
+// string fields nested within other values. Only the first entry of each key
+// is included.
+func (f Model) Strings() map[string]string {
+	strs := map[string]string{}
+	f.walkModelLeaves(func(path string, leaf reflect.Value) {
+		switch {
+		case leaf.Kind() == reflect.String:
+			strs[path] = leaf.String()
+		case leaf.Kind() == reflect.Slice && leaf.Type().ConvertibleTo(bytesType):
+			strs[path] = string(leaf.Bytes())
+		}
+	})
+	return strs
+}
+
+// FlatStringMap returns a text representation of each leaf within the model,
+// mapped by the path to the leaf, as accepted by GetPath. Strings are included
+// as is, while bools and numbers are formatted in the same way as FormatValue.
+// Values without components, such as a String, Float, or BrickColor, are
+// mapped by their key. Only the first entry of each key is included. The map
+// is a lossy export intended for configuration files and environment
+// variables, and cannot be converted back into a model.
+func (f Model) FlatStringMap() map[string]string {
+	strs := map[string]string{}
+	f.walkModelLeaves(func(path string, leaf reflect.Value) {
+		strs[path] = formatLeaf(leaf)
+	})
+	return strs
+}
+
+// formatLeaf formats a leaf as visited by walkLeaves.
+func formatLeaf(leaf reflect.Value) string {
+	switch leaf.Kind() {
+	case reflect.String:
+		return leaf.String()
+	case reflect.Slice:
+		return string(leaf.Bytes())
+	case reflect.Bool:
+		return strconv.FormatBool(leaf.Bool())
+	case reflect.Float32:
+		return formatFloat(float32(leaf.Float()))
+	case reflect.Float64:
+		return strconv.FormatFloat(leaf.Float(), 'g', -1, 64)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		return strconv.FormatInt(leaf.Int(), 10)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		return strconv.FormatUint(leaf.Uint(), 10)
+	}
+	return fmt.Sprint(leaf.Interface())
+}
+
+// ReplaceStrings returns a deep copy of the model in which the content of each
+// string, as described by Strings, is replaced by the result of replace.
+// replace receives the path to the string and its content. Unlike Strings,
+// every entry is visited, including those with a duplicate key. The model is
+// not modified.
+func (f Model) ReplaceStrings(replace func(path, value string) string) Model {
+	c := Model{Value: f.Value.Clone()}
+	for _, entry := range c.Value {
+		walkLeaves(entry.Key, reflect.ValueOf(entry.Value), func(path string, leaf reflect.Value) {
+			switch {
+			case leaf.Kind() == reflect.String:
+				leaf.SetString(replace(path, leaf.String()))
+			case leaf.Kind() == reflect.Slice && leaf.Type().ConvertibleTo(bytesType):
+				leaf.SetBytes([]byte(replace(path, string(leaf.Bytes()))))
+			}
+		})
+	}
+	return c
+}
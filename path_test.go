@@ -0,0 +1,275 @@
+package rbxattr_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func pathModel() rbxattr.Model {
+	return rbxattr.Model{
+		Value: rbxattr.ValueDictionary{
+			{Key: "Size", Value: &rbxattr.ValueUDim2{
+				X: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+				Y: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+			}},
+			{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+			{Key: "Sequence", Value: &rbxattr.ValueNumberSequence{
+				{Time: 0, Value: 1},
+				{Time: 1, Value: 2},
+			}},
+		},
+	}
+}
+
+func TestModelGetPath(t *testing.T) {
+	model := pathModel()
+	for path, want := range map[string]interface{}{
+		"Size.X.Scale":      float32(0.5),
+		"Size.Y.Offset":     int32(100),
+		"Size.X":            rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+		"Sequence[1].Value": float32(2),
+	} {
+		got, ok := model.GetPath(path)
+		if !ok {
+			t.Errorf("%s: failed to resolve", path)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %v, got %v", path, want, got)
+		}
+	}
+	if v, ok := model.GetPath("Size"); !ok || v != model.Value[0].Value {
+		t.Errorf("Size: expected attribute value, got %v", v)
+	}
+
+	for _, path := range []string{
+		"",
+		"Missing",
+		"Size.Z",
+		"Size.X.Scale.Foo",
+		"Size[0]",
+		"Sequence[2]",
+		"Sequence[-1]",
+		"Sequence[x]",
+		"Size..X",
+		"[0].X",
+	} {
+		if v, ok := model.GetPath(path); ok {
+			t.Errorf("%q: expected failure, got %v", path, v)
+		}
+	}
+}
+
+func TestModelSetPath(t *testing.T) {
+	model := pathModel()
+	if err := model.SetPath("Position.Y", float32(5)); err != nil {
+		t.Fatal(err)
+	}
+	want := rbxattr.ValueVector3{X: 1, Y: 5, Z: 3}
+	if got := *model.Value[1].Value.(*rbxattr.ValueVector3); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := model.SetPath("Size.X.Offset", int32(-20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.SetPath("Sequence[0].Value", float32(7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.SetPath("Size.Y", rbxattr.ValueUDim{Scale: 1}); err != nil {
+		t.Fatal(err)
+	}
+	wantSize := rbxattr.ValueUDim2{
+		X: rbxattr.ValueUDim{Scale: 0.5, Offset: -20},
+		Y: rbxattr.ValueUDim{Scale: 1},
+	}
+	if got := *model.Value[0].Value.(*rbxattr.ValueUDim2); got != wantSize {
+		t.Fatalf("expected %v, got %v", wantSize, got)
+	}
+	if got := (*model.Value[2].Value.(*rbxattr.ValueNumberSequence))[0].Value; got != 7 {
+		t.Fatalf("expected 7, got %v", got)
+	}
+
+	for _, c := range []struct {
+		path  string
+		value interface{}
+	}{
+		{"Position.W", float32(1)},
+		{"Missing.X", float32(1)},
+		{"Sequence[5].Value", float32(1)},
+		{"Position.X", "1"},
+		{"Position.X", 1e39},
+		{"Size.X.Offset", 1.5},
+		{"Size.X.Offset", int64(1) << 40},
+		{"Size.X.Offset", uint64(math.MaxUint64)},
+		{"Size.X.Offset", nil},
+		{"Size", rbxattr.ValueVector3{}},
+	} {
+		if err := model.SetPath(c.path, c.value); err == nil {
+			t.Errorf("%s = %#v: expected error", c.path, c.value)
+		}
+	}
+	if got := *model.Value[1].Value.(*rbxattr.ValueVector3); got != want {
+		t.Fatalf("failed set modified value: expected %v, got %v", want, got)
+	}
+}
+
+func TestModelSetPathNumeric(t *testing.T) {
+	model := pathModel()
+	// Numbers of another kind are converted when within range.
+	for _, c := range []struct {
+		path  string
+		value interface{}
+	}{
+		{"Size.X.Offset", 250},
+		{"Size.Y.Offset", -2.0},
+		{"Size.Y.Scale", uint8(3)},
+		{"Position.X", 2},
+		{"Position.Z", 0.1},
+	} {
+		if err := model.SetPath(c.path, c.value); err != nil {
+			t.Errorf("%s = %#v: unexpected error: %s", c.path, c.value, err)
+		}
+	}
+	wantSize := rbxattr.ValueUDim2{
+		X: rbxattr.ValueUDim{Scale: 0.5, Offset: 250},
+		Y: rbxattr.ValueUDim{Scale: 3, Offset: -2},
+	}
+	if got := *model.Value[0].Value.(*rbxattr.ValueUDim2); got != wantSize {
+		t.Errorf("expected %v, got %v", wantSize, got)
+	}
+	want := rbxattr.ValueVector3{X: 2, Y: 2, Z: 0.1}
+	if got := *model.Value[1].Value.(*rbxattr.ValueVector3); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	const msg = `path "Size.X.Offset": cannot set int32 to 1.5 (float64): out of range`
+	if err := model.SetPath("Size.X.Offset", 1.5); err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+}
+
+func TestModelStrings(t *testing.T) {
+	title := rbxattr.ValueString("Hello")
+	empty := rbxattr.ValueString("")
+	raw := rbxattr.ValueBytes("World")
+	shadowed := rbxattr.ValueString("Shadowed")
+	model := pathModel()
+	model.Value = append(model.Value,
+		rbxattr.Entry{Key: "Title", Value: &title},
+		rbxattr.Entry{Key: "Empty", Value: &empty},
+		rbxattr.Entry{Key: "Raw", Value: &raw},
+		rbxattr.Entry{Key: "Title", Value: &shadowed},
+		rbxattr.Entry{Key: "Font", Value: &rbxattr.ValueFont{Weight: 400, Family: "Family"}},
+	)
+	strs := model.Strings()
+	want := map[string]string{
+		"Title":             "Hello",
+		"Empty":             "",
+		"Raw":               "World",
+		"Font.Family":       "Family",
+		"Font.CachedFaceID": "",
+	}
+	if len(strs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, strs)
+	}
+	for path, s := range want {
+		if strs[path] != s {
+			t.Errorf("%s: expected %q, got %q", path, s, strs[path])
+		}
+		if _, ok := model.GetPath(path); !ok {
+			t.Errorf("%s: path does not resolve", path)
+		}
+	}
+}
+
+func TestModelFlatStringMap(t *testing.T) {
+	model := pathModel()
+	name := rbxattr.ValueString("foo")
+	enabled := rbxattr.ValueBool(true)
+	scale := rbxattr.ValueFloat(0.1)
+	brick := rbxattr.ValueBrickColor(194)
+	model.Value = append(model.Value,
+		rbxattr.Entry{Key: "Name", Value: &name},
+		rbxattr.Entry{Key: "Enabled", Value: &enabled},
+		rbxattr.Entry{Key: "Scale", Value: &scale},
+		rbxattr.Entry{Key: "Color", Value: &brick},
+		rbxattr.Entry{Key: "Name", Value: &enabled},
+	)
+	want := map[string]string{
+		"Size.X.Scale":         "0.5",
+		"Size.X.Offset":        "100",
+		"Size.Y.Scale":         "0.5",
+		"Size.Y.Offset":        "100",
+		"Position.X":           "1",
+		"Position.Y":           "2",
+		"Position.Z":           "3",
+		"Sequence[0].Envelope": "0",
+		"Sequence[0].Time":     "0",
+		"Sequence[0].Value":    "1",
+		"Sequence[1].Envelope": "0",
+		"Sequence[1].Time":     "1",
+		"Sequence[1].Value":    "2",
+		"Name":                 "foo",
+		"Enabled":              "true",
+		"Scale":                "0.1",
+		"Color":                "194",
+	}
+	got := model.FlatStringMap()
+	if len(got) != len(want) {
+		t.Errorf("expected %d entries, got %d", len(want), len(got))
+	}
+	for path, s := range want {
+		if got[path] != s {
+			t.Errorf("%s: expected %q, got %q", path, s, got[path])
+		}
+	}
+}
+
+func TestModelReplaceStrings(t *testing.T) {
+	title := rbxattr.ValueString("Hello")
+	raw := rbxattr.ValueBytes("World")
+	model := pathModel()
+	model.Value = append(model.Value,
+		rbxattr.Entry{Key: "Title", Value: &title},
+		rbxattr.Entry{Key: "Raw", Value: &raw},
+	)
+	original := model.Value.Clone()
+
+	reverse := func(s string) string {
+		r := []rune(s)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	}
+	paths := map[string]bool{}
+	replaced := model.ReplaceStrings(func(path, s string) string {
+		paths[path] = true
+		return reverse(s)
+	})
+	if len(paths) != 2 || !paths["Title"] || !paths["Raw"] {
+		t.Fatalf("unexpected paths visited: %v", paths)
+	}
+	strs := replaced.Strings()
+	if strs["Title"] != "olleH" || strs["Raw"] != "dlroW" {
+		t.Fatalf("unexpected replaced strings: %v", strs)
+	}
+	if !model.Value.Equal(original) {
+		t.Fatal("original model was modified")
+	}
+	if len(replaced.Value) != len(model.Value) {
+		t.Fatal("structure not preserved")
+	}
+	for i, entry := range replaced.Value[:3] {
+		if !(rbxattr.ValueDictionary{entry}).Equal(model.Value[i : i+1]) {
+			t.Errorf("non-string entry %q was modified", entry.Key)
+		}
+	}
+	replaced.Value[0].Value.(*rbxattr.ValueUDim2).X.Offset = 1
+	if model.Value[0].Value.(*rbxattr.ValueUDim2).X.Offset != 100 {
+		t.Fatal("copy shares values with original")
+	}
+}
@@ -0,0 +1,107 @@
+package rbxattr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// LengthMismatchError is returned by DecodeLengthPrefixed when the declared
+// length does not match the length of the model. This indicates a problem
+// with the framing, rather than the content of the model.
+type LengthMismatchError struct {
+	// Declared is the length declared by the prefix.
+	Declared int64
+	// Actual is the number of bytes consumed by the model, or -1 if the model
+	// extends beyond the declared length, in which case its length is not
+	// known.
+	Actual int64
+}
+
+func (err *LengthMismatchError) Error() string {
+	if err.Actual < 0 {
+		return fmt.Sprintf("length prefix: declared %d bytes, model is longer", err.Declared)
+	}
+	return fmt.Sprintf("length prefix: declared %d bytes, model has %d", err.Declared, err.Actual)
+}
+
+// DecodeLengthPrefixed decodes a Model that is preceded by its encoded length
+// as a uint32. Returns a *LengthMismatchError if the model does not consume
+// exactly the declared length.
+//
+// The declared number of bytes is read from r after the prefix before the
+// model is decoded, so that r is left at the end of the frame even if the
+// model cannot be decoded, and a following frame can still be read. Returns an
+// error without a model if r ends before the frame does.
+func DecodeLengthPrefixed(r io.Reader) (m Model, err error) {
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return m, fmt.Errorf("length prefix: %w", br.Err())
+	}
+	var frame bytes.Buffer
+	if _, err := io.CopyN(&frame, r, int64(length)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return m, fmt.Errorf("length prefix: frame: %w", err)
+	}
+	fr := bytes.NewReader(frame.Bytes())
+	n, err := m.ReadFrom(fr)
+	if err != nil {
+		if fr.Len() == 0 && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) {
+			return Model{}, &LengthMismatchError{Declared: int64(length), Actual: -1}
+		}
+		return Model{}, err
+	}
+	if n != int64(length) {
+		return Model{}, &LengthMismatchError{Declared: int64(length), Actual: n}
+	}
+	return m, nil
+}
+
+// EncodeLengthPrefixed encodes m preceded by its encoded length as a uint32.
+// Returns the total number of bytes written, including the prefix.
+func EncodeLengthPrefixed(w io.Writer, m Model) (n int64, err error) {
+	size := m.Size()
+	if size > math.MaxUint32 {
+		return 0, fmt.Errorf("length prefix: model size %d exceeds maximum", size)
+	}
+	bw := newBinaryWriter(w)
+	if bw.Number(uint32(size)) {
+		return bw.N(), fmt.Errorf("length prefix: %w", bw.Err())
+	}
+	bw.Add(m.WriteTo(w))
+	return bw.End()
+}
+
+// WrapForProperty returns m encoded as the value of the AttributesSerialize
+// property of an instance in Roblox's binary model format (rbxm and rbxl). The
+// property has the String type, whose values are encoded as the length of the
+// string as a uint32, followed by its bytes, so the result is the same as that
+// of EncodeLengthPrefixed. Within a PROP chunk, the values of each instance are
+// concatenated in order of the instances of the chunk.
+func WrapForProperty(m Model) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := EncodeLengthPrefixed(&buf, m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnwrapFromProperty decodes a Model from b, which is the value of the
+// AttributesSerialize property of an instance, as returned by WrapForProperty.
+// Returns an error if b contains bytes beyond the value.
+func UnwrapFromProperty(b []byte) (Model, error) {
+	r := bytes.NewReader(b)
+	m, err := DecodeLengthPrefixed(r)
+	if err != nil {
+		return Model{}, err
+	}
+	if r.Len() > 0 {
+		return Model{}, fmt.Errorf("length prefix: %d bytes of trailing data", r.Len())
+	}
+	return m, nil
+}
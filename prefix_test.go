@@ -0,0 +1,151 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestDecodeLengthPrefixed(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Position", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+	}
+	data := encode(t, dict)
+	prefixed := func(length int, trailer ...byte) *bytes.Reader {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, uint32(length))
+		buf.Write(data)
+		buf.Write(trailer)
+		return bytes.NewReader(buf.Bytes())
+	}
+
+	r := prefixed(len(data), 0xFF)
+	model, err := rbxattr.DecodeLengthPrefixed(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !model.Value.Equal(dict) {
+		t.Fatal("decoded model does not match")
+	}
+	if r.Len() != 1 {
+		t.Fatalf("expected trailing byte to remain unread, %d bytes remain", r.Len())
+	}
+
+	for _, c := range []struct {
+		declared int
+		actual   int64
+	}{
+		{len(data) - 1, -1},
+		{0, -1},
+		{len(data) + 1, int64(len(data))},
+	} {
+		_, err := rbxattr.DecodeLengthPrefixed(prefixed(c.declared, 0, 0, 0, 0))
+		var mismatch *rbxattr.LengthMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Errorf("length %d: expected LengthMismatchError, got %v", c.declared, err)
+			continue
+		}
+		if mismatch.Declared != int64(c.declared) || mismatch.Actual != c.actual {
+			t.Errorf("length %d: expected mismatch %d/%d, got %d/%d", c.declared, c.declared, c.actual, mismatch.Declared, mismatch.Actual)
+		}
+	}
+
+	// A frame that is longer than its model is skipped entirely, so that the
+	// next frame can be decoded.
+	var frames bytes.Buffer
+	binary.Write(&frames, binary.LittleEndian, uint32(len(data)+2))
+	frames.Write(data)
+	frames.Write([]byte{0xFF, 0xFF})
+	binary.Write(&frames, binary.LittleEndian, uint32(len(data)))
+	frames.Write(data)
+	var mismatch *rbxattr.LengthMismatchError
+	if _, err := rbxattr.DecodeLengthPrefixed(&frames); !errors.As(err, &mismatch) {
+		t.Fatalf("long frame: expected LengthMismatchError, got %v", err)
+	}
+	if model, err := rbxattr.DecodeLengthPrefixed(&frames); err != nil || !model.Value.Equal(dict) {
+		t.Fatalf("frame after long frame: expected model, got error %v", err)
+	}
+	if frames.Len() != 0 {
+		t.Fatalf("expected all frames to be read, %d bytes remain", frames.Len())
+	}
+
+	// Corrupt content within a correct frame is not a mismatch.
+	corrupt := append([]byte(nil), data...)
+	corrupt[4+4+len("Name")] = 0xFF
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(len(corrupt)))
+	buf.Write(corrupt)
+	_, err = rbxattr.DecodeLengthPrefixed(&buf)
+	if err == nil || errors.As(err, &mismatch) {
+		t.Errorf("corrupt content: expected content error, got %v", err)
+	}
+}
+
+func TestEncodeLengthPrefixed(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{X: rbxattr.ValueUDim{Scale: 1}}},
+	}}
+	var buf bytes.Buffer
+	n, err := rbxattr.EncodeLengthPrefixed(&buf, model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) || n != 4+model.Size() {
+		t.Fatalf("expected %d bytes written, got %d", 4+model.Size(), n)
+	}
+	if prefix := binary.LittleEndian.Uint32(buf.Bytes()); int64(prefix) != model.Size() {
+		t.Fatalf("expected prefix %d, got %d", model.Size(), prefix)
+	}
+
+	decoded, err := rbxattr.DecodeLengthPrefixed(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Value.Equal(model.Value) {
+		t.Fatal("decoded model does not match")
+	}
+}
+
+func TestWrapForProperty(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/corpus/udim2.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model rbxattr.Model
+	if _, err := model.ReadFrom(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	// A String property value: uint32 length 58, followed by the blob.
+	want := append([]byte{0x3A, 0x00, 0x00, 0x00}, data...)
+
+	got, err := rbxattr.WrapForProperty(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected bytes\n\t%X\ngot\n\t%X", want, got)
+	}
+
+	unwrapped, err := rbxattr.UnwrapFromProperty(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !unwrapped.Value.Equal(model.Value) {
+		t.Fatal("unwrapped model does not match")
+	}
+	const trailing = "length prefix: 1 bytes of trailing data"
+	if _, err := rbxattr.UnwrapFromProperty(append(got, 0)); err == nil || err.Error() != trailing {
+		t.Errorf("expected error %q, got %v", trailing, err)
+	}
+	if _, err := rbxattr.UnwrapFromProperty(got[:len(got)-1]); err == nil {
+		t.Error("truncated: expected error")
+	}
+}
@@ -0,0 +1,315 @@
+// The rbxattrplain package converts models of the rbxattr package to and from
+// plain structs that contain no interfaces, which suits code generators such
+// as those of protobuf.
+//
+// A PlainValue corresponds to a oneof: at most one of its fields is set,
+// according to the type of the value, and none is set for a nil value. Each
+// field is a pointer, so that a zero value, such as an empty string or an empty
+// sequence, can be distinguished from an unset field. Components use only
+// types that have a protobuf equivalent, so integers narrower than 32 bits are
+// widened, and arrays are represented as slices.
+package rbxattrplain
+
+import (
+	"github.com/robloxapi/rbxattr"
+)
+
+// PlainModel is the plain form of an rbxattr.Model.
+type PlainModel struct {
+	Entries []PlainEntry
+}
+
+// PlainEntry is the plain form of an rbxattr.Entry.
+type PlainEntry struct {
+	Key   string
+	Value PlainValue
+}
+
+// PlainValue is the plain form of an rbxattr.Value. At most one field is set.
+type PlainValue struct {
+	String         *string
+	Bool           *bool
+	Float          *float32
+	Double         *float64
+	UDim           *UDim
+	UDim2          *UDim2
+	BrickColor     *uint32
+	Color3         *Color3
+	Vector2        *Vector2
+	Vector3        *Vector3
+	CFrame         *CFrame
+	NumberSequence *NumberSequence
+	ColorSequence  *ColorSequence
+	NumberRange    *NumberRange
+	Rect           *Rect
+	Font           *Font
+}
+
+type UDim struct {
+	Scale  float32
+	Offset int32
+}
+
+type UDim2 struct {
+	X UDim
+	Y UDim
+}
+
+type Color3 struct {
+	R float32
+	G float32
+	B float32
+}
+
+type Vector2 struct {
+	X float32
+	Y float32
+}
+
+type Vector3 struct {
+	X float32
+	Y float32
+	Z float32
+}
+
+// CFrame is the plain form of rbxattr.ValueCFrame. Rotation has 9 components.
+type CFrame struct {
+	Position Vector3
+	Rotation []float32
+}
+
+type NumberSequence struct {
+	Keypoints []NumberSequenceKeypoint
+}
+
+type NumberSequenceKeypoint struct {
+	Envelope float32
+	Time     float32
+	Value    float32
+}
+
+type ColorSequence struct {
+	Keypoints []ColorSequenceKeypoint
+}
+
+type ColorSequenceKeypoint struct {
+	Envelope float32
+	Time     float32
+	Value    Color3
+}
+
+type NumberRange struct {
+	Min float32
+	Max float32
+}
+
+type Rect struct {
+	Min Vector2
+	Max Vector2
+}
+
+// Font is the plain form of rbxattr.ValueFont. Weight and Style are widened to
+// uint32.
+type Font struct {
+	Weight       uint32
+	Style        uint32
+	Family       string
+	CachedFaceID string
+}
+
+// ToPlain returns the plain form of f. A String value of type ValueBytes
+// becomes a string, and a Float value of type ValueFloatWide is narrowed to
+// float32, as it would be when encoded. A value of a type not implemented by
+// the rbxattr package, including a nil value, becomes a PlainValue with no
+// field set.
+func ToPlain(f rbxattr.Model) PlainModel {
+	var p PlainModel
+	if f.Value != nil {
+		p.Entries = make([]PlainEntry, len(f.Value))
+	}
+	for i, entry := range f.Value {
+		p.Entries[i] = PlainEntry{Key: entry.Key, Value: toPlainValue(entry.Value)}
+	}
+	return p
+}
+
+// FromPlain returns the model represented by p. An entry whose PlainValue has
+// no field set receives a nil value, which cannot be encoded. If more than one
+// field is set, the first, in order of declaration, is used. Rotation
+// components of a CFrame beyond the ninth are ignored, and missing components
+// are zero. The Weight and Style of a Font are truncated to the widths of the
+// fields of rbxattr.ValueFont.
+func FromPlain(p PlainModel) rbxattr.Model {
+	var f rbxattr.Model
+	if p.Entries != nil {
+		f.Value = make(rbxattr.ValueDictionary, len(p.Entries))
+	}
+	for i, entry := range p.Entries {
+		f.Value[i] = rbxattr.Entry{Key: entry.Key, Value: fromPlainValue(entry.Value)}
+	}
+	return f
+}
+
+func toPlainUDim(v rbxattr.ValueUDim) UDim {
+	return UDim{Scale: v.Scale, Offset: v.Offset}
+}
+
+func toPlainColor3(v rbxattr.ValueColor3) Color3 {
+	return Color3{R: v.R, G: v.G, B: v.B}
+}
+
+func toPlainVector2(v rbxattr.ValueVector2) Vector2 {
+	return Vector2{X: v.X, Y: v.Y}
+}
+
+func toPlainVector3(v rbxattr.ValueVector3) Vector3 {
+	return Vector3{X: v.X, Y: v.Y, Z: v.Z}
+}
+
+func toPlainValue(v rbxattr.Value) PlainValue {
+	var p PlainValue
+	switch v := v.(type) {
+	case *rbxattr.ValueString:
+		s := string(*v)
+		p.String = &s
+	case *rbxattr.ValueBytes:
+		s := string(*v)
+		p.String = &s
+	case *rbxattr.ValueBool:
+		b := bool(*v)
+		p.Bool = &b
+	case *rbxattr.ValueFloat:
+		f := float32(*v)
+		p.Float = &f
+	case *rbxattr.ValueFloatWide:
+		c, _ := rbxattr.Coerce(v, rbxattr.TypeFloat)
+		f := float32(*c.(*rbxattr.ValueFloat))
+		p.Float = &f
+	case *rbxattr.ValueDouble:
+		f := float64(*v)
+		p.Double = &f
+	case *rbxattr.ValueUDim:
+		u := toPlainUDim(*v)
+		p.UDim = &u
+	case *rbxattr.ValueUDim2:
+		p.UDim2 = &UDim2{X: toPlainUDim(v.X), Y: toPlainUDim(v.Y)}
+	case *rbxattr.ValueBrickColor:
+		c := uint32(*v)
+		p.BrickColor = &c
+	case *rbxattr.ValueColor3:
+		c := toPlainColor3(*v)
+		p.Color3 = &c
+	case *rbxattr.ValueVector2:
+		u := toPlainVector2(*v)
+		p.Vector2 = &u
+	case *rbxattr.ValueVector3:
+		u := toPlainVector3(*v)
+		p.Vector3 = &u
+	case *rbxattr.ValueCFrame:
+		rotation := make([]float32, len(v.Rotation))
+		copy(rotation, v.Rotation[:])
+		p.CFrame = &CFrame{Position: toPlainVector3(v.Position), Rotation: rotation}
+	case *rbxattr.ValueNumberSequence:
+		s := &NumberSequence{Keypoints: make([]NumberSequenceKeypoint, len(*v))}
+		for i, k := range *v {
+			s.Keypoints[i] = NumberSequenceKeypoint{Envelope: k.Envelope, Time: k.Time, Value: k.Value}
+		}
+		p.NumberSequence = s
+	case *rbxattr.ValueColorSequence:
+		s := &ColorSequence{Keypoints: make([]ColorSequenceKeypoint, len(*v))}
+		for i, k := range *v {
+			s.Keypoints[i] = ColorSequenceKeypoint{Envelope: k.Envelope, Time: k.Time, Value: toPlainColor3(k.Value)}
+		}
+		p.ColorSequence = s
+	case *rbxattr.ValueNumberRange:
+		p.NumberRange = &NumberRange{Min: v.Min, Max: v.Max}
+	case *rbxattr.ValueRect:
+		p.Rect = &Rect{Min: toPlainVector2(v.Min), Max: toPlainVector2(v.Max)}
+	case *rbxattr.ValueFont:
+		p.Font = &Font{
+			Weight:       uint32(v.Weight),
+			Style:        uint32(v.Style),
+			Family:       v.Family,
+			CachedFaceID: v.CachedFaceID,
+		}
+	}
+	return p
+}
+
+func fromPlainUDim(p UDim) rbxattr.ValueUDim {
+	return rbxattr.ValueUDim{Scale: p.Scale, Offset: p.Offset}
+}
+
+func fromPlainColor3(p Color3) rbxattr.ValueColor3 {
+	return rbxattr.ValueColor3{R: p.R, G: p.G, B: p.B}
+}
+
+func fromPlainVector2(p Vector2) rbxattr.ValueVector2 {
+	return rbxattr.ValueVector2{X: p.X, Y: p.Y}
+}
+
+func fromPlainVector3(p Vector3) rbxattr.ValueVector3 {
+	return rbxattr.ValueVector3{X: p.X, Y: p.Y, Z: p.Z}
+}
+
+func fromPlainValue(p PlainValue) rbxattr.Value {
+	switch {
+	case p.String != nil:
+		v := rbxattr.ValueString(*p.String)
+		return &v
+	case p.Bool != nil:
+		v := rbxattr.ValueBool(*p.Bool)
+		return &v
+	case p.Float != nil:
+		v := rbxattr.ValueFloat(*p.Float)
+		return &v
+	case p.Double != nil:
+		v := rbxattr.ValueDouble(*p.Double)
+		return &v
+	case p.UDim != nil:
+		v := fromPlainUDim(*p.UDim)
+		return &v
+	case p.UDim2 != nil:
+		return &rbxattr.ValueUDim2{X: fromPlainUDim(p.UDim2.X), Y: fromPlainUDim(p.UDim2.Y)}
+	case p.BrickColor != nil:
+		v := rbxattr.ValueBrickColor(*p.BrickColor)
+		return &v
+	case p.Color3 != nil:
+		v := fromPlainColor3(*p.Color3)
+		return &v
+	case p.Vector2 != nil:
+		v := fromPlainVector2(*p.Vector2)
+		return &v
+	case p.Vector3 != nil:
+		v := fromPlainVector3(*p.Vector3)
+		return &v
+	case p.CFrame != nil:
+		v := &rbxattr.ValueCFrame{Position: fromPlainVector3(p.CFrame.Position)}
+		copy(v.Rotation[:], p.CFrame.Rotation)
+		return v
+	case p.NumberSequence != nil:
+		v := make(rbxattr.ValueNumberSequence, len(p.NumberSequence.Keypoints))
+		for i, k := range p.NumberSequence.Keypoints {
+			v[i] = rbxattr.ValueNumberSequenceKeypoint{Envelope: k.Envelope, Time: k.Time, Value: k.Value}
+		}
+		return &v
+	case p.ColorSequence != nil:
+		v := make(rbxattr.ValueColorSequence, len(p.ColorSequence.Keypoints))
+		for i, k := range p.ColorSequence.Keypoints {
+			v[i] = rbxattr.ValueColorSequenceKeypoint{Envelope: k.Envelope, Time: k.Time, Value: fromPlainColor3(k.Value)}
+		}
+		return &v
+	case p.NumberRange != nil:
+		return &rbxattr.ValueNumberRange{Min: p.NumberRange.Min, Max: p.NumberRange.Max}
+	case p.Rect != nil:
+		return &rbxattr.ValueRect{Min: fromPlainVector2(p.Rect.Min), Max: fromPlainVector2(p.Rect.Max)}
+	case p.Font != nil:
+		return &rbxattr.ValueFont{
+			Weight:       uint16(p.Font.Weight),
+			Style:        uint8(p.Font.Style),
+			Family:       p.Font.Family,
+			CachedFaceID: p.Font.CachedFaceID,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package rbxattrplain_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+	"github.com/robloxapi/rbxattr/rbxattrplain"
+	"github.com/robloxapi/rbxattr/rbxattrtest"
+)
+
+func TestPlainRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		model := rbxattrtest.RandomModel(rng, 20)
+		got := rbxattrplain.FromPlain(rbxattrplain.ToPlain(model))
+		if !got.Value.Equal(model.Value) {
+			t.Fatalf("model %d: round trip does not match", i)
+		}
+	}
+}
+
+func TestPlainCorpus(t *testing.T) {
+	b, err := ioutil.ReadFile("../testdata/corpus/all_types.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model rbxattr.Model
+	if _, err := model.ReadFrom(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+	plain := rbxattrplain.ToPlain(model)
+	for _, entry := range plain.Entries {
+		if entry.Value == (rbxattrplain.PlainValue{}) {
+			t.Errorf("%s: expected value to be set", entry.Key)
+		}
+	}
+	if got := rbxattrplain.FromPlain(plain); !got.Value.Equal(model.Value) {
+		t.Error("round trip does not match")
+	}
+}
+
+func TestPlainUnset(t *testing.T) {
+	model := rbxattrplain.FromPlain(rbxattrplain.PlainModel{
+		Entries: []rbxattrplain.PlainEntry{{Key: "Unset"}},
+	})
+	if len(model.Value) != 1 || model.Value[0].Value != nil {
+		t.Fatalf("expected one entry with nil value, got %v", model.Value)
+	}
+	plain := rbxattrplain.ToPlain(model)
+	if plain.Entries[0].Value != (rbxattrplain.PlainValue{}) {
+		t.Error("expected nil value to have no field set")
+	}
+}
+
+func TestPlainFloatWideNaN(t *testing.T) {
+	// A signaling NaN is narrowed as it is when encoded, which retains the
+	// upper bits of its payload without quieting it.
+	wide := rbxattr.ValueFloatWide(math.Float64frombits(0xFFF2468ACE13579B))
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{{Key: "NaN", Value: &wide}}}
+	plain := rbxattrplain.ToPlain(model)
+	if f := plain.Entries[0].Value.Float; f == nil || math.Float32bits(*f) != 0xFF923456 {
+		t.Fatalf("expected narrowed NaN, got %v", f)
+	}
+	if got := rbxattrplain.FromPlain(plain); !got.Value.Equal(model.Value) {
+		t.Error("round trip does not match encoding")
+	}
+}
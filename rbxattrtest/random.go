@@ -0,0 +1,170 @@
+// The rbxattrtest package provides utilities for testing code that uses the
+// rbxattr package.
+package rbxattrtest
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+const keyChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_"
+
+// RandomModel returns a model with up to maxEntries entries, each with a
+// random key and a random value of a random implemented type. Keys are
+// distinct, are valid attribute names, and are not reserved. The model always
+// encodes and decodes without error. The result depends only on the state of
+// rng.
+func RandomModel(rng *rand.Rand, maxEntries int) rbxattr.Model {
+	n := 0
+	if maxEntries > 0 {
+		n = rng.Intn(maxEntries + 1)
+	}
+	seen := make(map[string]bool, n)
+	dict := make(rbxattr.ValueDictionary, 0, n)
+	for len(dict) < n {
+		key := randomKey(rng)
+		if seen[key] || rbxattr.IsReserved(key) {
+			continue
+		}
+		seen[key] = true
+		dict = append(dict, rbxattr.Entry{Key: key, Value: RandomValue(rng)})
+	}
+	return rbxattr.Model{Value: dict}
+}
+
+// randomKey returns a random valid attribute name.
+func randomKey(rng *rand.Rand) string {
+	var b strings.Builder
+	length := 1 + rng.Intn(20)
+	for i := 0; i < length; i++ {
+		b.WriteByte(keyChars[rng.Intn(len(keyChars))])
+	}
+	return b.String()
+}
+
+// randomFloat returns a random finite float32.
+func randomFloat(rng *rand.Rand) float32 {
+	return (rng.Float32()*2 - 1) * 1000
+}
+
+func randomVector2(rng *rand.Rand) rbxattr.ValueVector2 {
+	return rbxattr.ValueVector2{X: randomFloat(rng), Y: randomFloat(rng)}
+}
+
+func randomVector3(rng *rand.Rand) rbxattr.ValueVector3 {
+	return rbxattr.ValueVector3{X: randomFloat(rng), Y: randomFloat(rng), Z: randomFloat(rng)}
+}
+
+func randomUDim(rng *rand.Rand) rbxattr.ValueUDim {
+	return rbxattr.ValueUDim{Scale: rng.Float32(), Offset: int32(rng.Uint32())}
+}
+
+func randomColor3(rng *rand.Rand) rbxattr.ValueColor3 {
+	return rbxattr.ValueColor3{R: rng.Float32(), G: rng.Float32(), B: rng.Float32()}
+}
+
+// randomTypes contains each type produced by RandomValue.
+var randomTypes = []rbxattr.Type{
+	rbxattr.TypeString,
+	rbxattr.TypeBool,
+	rbxattr.TypeFloat,
+	rbxattr.TypeDouble,
+	rbxattr.TypeUDim,
+	rbxattr.TypeUDim2,
+	rbxattr.TypeBrickColor,
+	rbxattr.TypeColor3,
+	rbxattr.TypeVector2,
+	rbxattr.TypeVector3,
+	rbxattr.TypeCFrame,
+	rbxattr.TypeNumberSequence,
+	rbxattr.TypeColorSequence,
+	rbxattr.TypeNumberRange,
+	rbxattr.TypeRect,
+	rbxattr.TypeFont,
+}
+
+// RandomValue returns a random value of a random implemented type.
+func RandomValue(rng *rand.Rand) rbxattr.Value {
+	switch randomTypes[rng.Intn(len(randomTypes))] {
+	case rbxattr.TypeString:
+		b := make([]byte, rng.Intn(32))
+		rng.Read(b)
+		v := rbxattr.ValueString(b)
+		return &v
+	case rbxattr.TypeBool:
+		v := rbxattr.ValueBool(rng.Intn(2) == 1)
+		return &v
+	case rbxattr.TypeFloat:
+		v := rbxattr.ValueFloat(randomFloat(rng))
+		return &v
+	case rbxattr.TypeDouble:
+		v := rbxattr.ValueDouble(rng.NormFloat64() * 1000)
+		return &v
+	case rbxattr.TypeUDim:
+		v := randomUDim(rng)
+		return &v
+	case rbxattr.TypeUDim2:
+		return &rbxattr.ValueUDim2{X: randomUDim(rng), Y: randomUDim(rng)}
+	case rbxattr.TypeBrickColor:
+		// The largest BrickColor number is 1032.
+		v := rbxattr.ValueBrickColor(rng.Intn(1033))
+		return &v
+	case rbxattr.TypeColor3:
+		v := randomColor3(rng)
+		return &v
+	case rbxattr.TypeVector2:
+		v := randomVector2(rng)
+		return &v
+	case rbxattr.TypeVector3:
+		v := randomVector3(rng)
+		return &v
+	case rbxattr.TypeCFrame:
+		v := rbxattr.ValueCFrame{Position: randomVector3(rng)}
+		if rng.Intn(2) == 0 {
+			// Identity, which is encoded with a rotation ID.
+			v.Rotation = [9]float32{1, 0, 0, 0, 1, 0, 0, 0, 1}
+		} else {
+			for i := range v.Rotation {
+				v.Rotation[i] = rng.Float32()*2 - 1
+			}
+		}
+		return &v
+	case rbxattr.TypeNumberSequence:
+		v := make(rbxattr.ValueNumberSequence, rng.Intn(6))
+		for i := range v {
+			v[i] = rbxattr.ValueNumberSequenceKeypoint{
+				Envelope: rng.Float32(),
+				Time:     rng.Float32(),
+				Value:    randomFloat(rng),
+			}
+		}
+		return &v
+	case rbxattr.TypeColorSequence:
+		v := make(rbxattr.ValueColorSequence, rng.Intn(6))
+		for i := range v {
+			v[i] = rbxattr.ValueColorSequenceKeypoint{
+				Envelope: rng.Float32(),
+				Time:     rng.Float32(),
+				Value:    randomColor3(rng),
+			}
+		}
+		return &v
+	case rbxattr.TypeNumberRange:
+		return &rbxattr.ValueNumberRange{Min: randomFloat(rng), Max: randomFloat(rng)}
+	case rbxattr.TypeRect:
+		return &rbxattr.ValueRect{Min: randomVector2(rng), Max: randomVector2(rng)}
+	case rbxattr.TypeFont:
+		v := rbxattr.ValueFont{
+			Weight: uint16(rng.Intn(9)+1) * 100,
+			Style:  uint8(rng.Intn(2)),
+			Family: "rbxasset://fonts/families/SourceSansPro.json",
+		}
+		if rng.Intn(2) == 0 {
+			v.CachedFaceID = "rbxasset://fonts/SourceSansPro-Regular.ttf"
+		}
+		return &v
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,37 @@
+package rbxattrtest_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+	"github.com/robloxapi/rbxattr/rbxattrtest"
+)
+
+func TestRandomModel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		model := rbxattrtest.RandomModel(rng, 20)
+		if len(model.Value) > 20 {
+			t.Fatalf("model %d: expected at most 20 entries, got %d", i, len(model.Value))
+		}
+		if err := model.Validate(); err != nil {
+			t.Fatalf("model %d: %s", i, err)
+		}
+		var buf bytes.Buffer
+		if _, err := model.WriteTo(&buf); err != nil {
+			t.Fatalf("model %d: %s", i, err)
+		}
+		if err := rbxattr.Validate(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatalf("model %d: %s", i, err)
+		}
+		var decoded rbxattr.Model
+		if _, err := decoded.ReadFrom(&buf); err != nil {
+			t.Fatalf("model %d: %s", i, err)
+		}
+		if !decoded.Value.Equal(model.Value) {
+			t.Fatalf("model %d: decoded model does not match", i)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package rbxattr
+
+import (
+	"fmt"
+	"io"
+)
+
+// fixedSizes maps each Type whose encoding has a fixed size to that size.
+var fixedSizes = map[Type]int64{
+	TypeBool:        1,
+	TypeFloat:       4,
+	TypeDouble:      8,
+	TypeUDim:        8,
+	TypeUDim2:       16,
+	TypeBrickColor:  4,
+	TypeColor3:      12,
+	TypeVector2:     8,
+	TypeVector3:     12,
+	TypeNumberRange: 8,
+	TypeRect:        16,
+}
+
+// keypointSizes maps each sequence Type to the size of one keypoint.
+var keypointSizes = map[Type]int64{
+	TypeNumberSequence: 12,
+	TypeColorSequence:  20,
+}
+
+// skipValue reads and discards a value of the given Type from r, without
+// decoding it. Errors have the same structure as the errors of the
+// corresponding ReadFrom.
+func skipValue(r io.Reader, typ Type) (n int64, err error) {
+	br := newBinaryReader(r)
+	if size, ok := fixedSizes[typ]; ok {
+		if br.Skip(size) {
+			return br.N(), fmt.Errorf("%s: %w", typ, br.Err())
+		}
+		return br.End()
+	}
+	switch typ {
+	case TypeString:
+		var length uint32
+		if br.Number(&length) || br.Skip(int64(length)) {
+			return br.N(), fmt.Errorf("String: %w", br.Err())
+		}
+	case TypeNumberSequence, TypeColorSequence:
+		var length uint32
+		if br.Number(&length) {
+			return br.N(), fmt.Errorf("%s length: %w", typ, br.Err())
+		}
+		if br.Skip(int64(length) * keypointSizes[typ]) {
+			return br.N(), fmt.Errorf("%s: %w", typ, br.Err())
+		}
+	case TypeCFrame:
+		if br.Skip(12) {
+			return br.N(), fmt.Errorf("CFrame.Position: %w", br.Err())
+		}
+		var id uint8
+		if br.Number(&id) {
+			return br.N(), fmt.Errorf("CFrame.ID: %w", br.Err())
+		}
+		if id == 0 && br.Skip(36) {
+			return br.N(), fmt.Errorf("CFrame.Rotation: %w", br.Err())
+		}
+	case TypeFont:
+		if br.Skip(3) {
+			return br.N(), fmt.Errorf("Font: %w", br.Err())
+		}
+		var length uint32
+		if br.Number(&length) || br.Skip(int64(length)) {
+			return br.N(), fmt.Errorf("Font.Family: %w", br.Err())
+		}
+		if br.Number(&length) || br.Skip(int64(length)) {
+			return br.N(), fmt.Errorf("Font.CachedFaceID: %w", br.Err())
+		}
+	default:
+		return 0, &UnknownTypeError{Types: []Type{typ}}
+	}
+	return br.End()
+}
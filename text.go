@@ -0,0 +1,438 @@
+package rbxattr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatValue returns a compact, human-readable representation of v. Strings
+// are returned as is, a Bool is either "true" or "false", and numbers are
+// formatted in the shortest form that represents them exactly. Other values
+// are formatted as the name of their type followed by their components, such
+// as "UDim2(0.5, 100, 0.5, 100)" or "Color3(#FF8000)". A Font is formatted
+// as its family, weight, and style, omitting its cached face ID. Sequences are
+// summarized by their length, such as "NumberSequence[3]".
+func FormatValue(v Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case *ValueString:
+		return string(*v)
+	case *ValueBytes:
+		return string(*v)
+	case *ValueBool:
+		return strconv.FormatBool(bool(*v))
+	case *ValueFloat:
+		return formatFloat(float32(*v))
+	case *ValueFloatWide:
+		return strconv.FormatFloat(float64(*v), 'g', -1, 64)
+	case *ValueDouble:
+		return strconv.FormatFloat(float64(*v), 'g', -1, 64)
+	case *ValueUDim:
+		return formatArgs(v.Type(), formatFloat(v.Scale), strconv.Itoa(int(v.Offset)))
+	case *ValueUDim2:
+		return formatArgs(v.Type(),
+			formatFloat(v.X.Scale), strconv.Itoa(int(v.X.Offset)),
+			formatFloat(v.Y.Scale), strconv.Itoa(int(v.Y.Offset)),
+		)
+	case *ValueBrickColor:
+		return formatArgs(v.Type(), strconv.FormatUint(uint64(*v), 10))
+	case *ValueColor3:
+		c := v.NRGBA()
+		return formatArgs(v.Type(), fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B))
+	case *ValueVector2:
+		return formatArgs(v.Type(), formatFloat(v.X), formatFloat(v.Y))
+	case *ValueVector3:
+		return formatArgs(v.Type(), formatFloat(v.X), formatFloat(v.Y), formatFloat(v.Z))
+	case *ValueCFrame:
+		args := []string{formatFloat(v.Position.X), formatFloat(v.Position.Y), formatFloat(v.Position.Z)}
+		for _, r := range v.Rotation {
+			args = append(args, formatFloat(r))
+		}
+		return formatArgs(v.Type(), args...)
+	case *ValueNumberSequence:
+		return fmt.Sprintf("%s[%d]", v.Type(), len(*v))
+	case *ValueColorSequence:
+		return fmt.Sprintf("%s[%d]", v.Type(), len(*v))
+	case *ValueNumberRange:
+		return formatArgs(v.Type(), formatFloat(v.Min), formatFloat(v.Max))
+	case *ValueRect:
+		return formatArgs(v.Type(),
+			formatFloat(v.Min.X), formatFloat(v.Min.Y),
+			formatFloat(v.Max.X), formatFloat(v.Max.Y),
+		)
+	case *ValueFont:
+		return formatArgs(v.Type(), v.Family, strconv.Itoa(int(v.Weight)), strconv.Itoa(int(v.Style)))
+	}
+	return fmt.Sprintf("%s(%v)", v.Type(), v)
+}
+
+// formatFloat formats f in the shortest form that represents it exactly.
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
+
+// formatArgs formats the name of typ followed by a parenthesized list of args.
+func formatArgs(typ Type, args ...string) string {
+	return typ.String() + "(" + strings.Join(args, ", ") + ")"
+}
+
+// ParseValue parses s, as formatted by FormatValue, into a Value of the given
+// Type. Components of structured values are separated by commas, and may be
+// surrounded by spaces. Sequences cannot be parsed, as FormatValue only
+// summarizes them, and neither can a Font, as FormatValue omits its cached face
+// ID. A Color3 parsed from its hexadecimal form has the precision
+// of a byte per component.
+func ParseValue(typ Type, s string) (Value, error) {
+	switch typ {
+	case TypeString:
+		v := ValueString(s)
+		return &v, nil
+	case TypeBool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("Bool: invalid value %q", s)
+		}
+		v := ValueBool(b)
+		return &v, nil
+	case TypeFloat:
+		f, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Float: invalid value %q", s)
+		}
+		v := ValueFloat(f)
+		return &v, nil
+	case TypeDouble:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Double: invalid value %q", s)
+		}
+		v := ValueDouble(f)
+		return &v, nil
+	case TypeNumberSequence, TypeColorSequence, TypeFont:
+		return nil, fmt.Errorf("cannot parse value of type %s", typ)
+	}
+	if NewValue(typ) == nil {
+		return nil, fmt.Errorf("cannot parse value of type %s", typ)
+	}
+	name := typ.String()
+	if !strings.HasPrefix(s, name+"(") || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("%s: expected %s(...), got %q", name, name, s)
+	}
+	s = s[len(name)+1 : len(s)-1]
+	var args []string
+	if strings.TrimSpace(s) != "" {
+		args = strings.Split(s, ",")
+	}
+	return valueFromArgs(typ, args)
+}
+
+// ParseArgs parses arguments of the form "Key=Value" or "Key:Type=Value", such
+// as command-line arguments, into a model with an entry for each argument, in
+// order. Type is the name of a type, compared case-insensitively, and defaults
+// to String. Value is parsed by ParseValue, except that the components of a
+// structured value may be given without the name of the type, such as
+// "Position:Vector3=1,2,3".
+func ParseArgs(args []string) (Model, error) {
+	d := make(ValueDictionary, 0, len(args))
+	for i, arg := range args {
+		eq := strings.Index(arg, "=")
+		if eq < 0 {
+			return Model{}, fmt.Errorf("args[%d]: expected Key=Value, got %q", i, arg)
+		}
+		key, s := arg[:eq], arg[eq+1:]
+		typ := TypeString
+		if colon := strings.Index(key, ":"); colon >= 0 {
+			var ok bool
+			if typ, ok = typeFromName(key[colon+1:]); !ok {
+				return Model{}, fmt.Errorf("args[%d]: unknown type %q", i, key[colon+1:])
+			}
+			key = key[:colon]
+		}
+		if key == "" {
+			return Model{}, fmt.Errorf("args[%d]: empty key", i)
+		}
+		switch typ {
+		case TypeString, TypeBool, TypeFloat, TypeDouble:
+		default:
+			if !strings.HasPrefix(s, typ.String()+"(") {
+				s = typ.String() + "(" + s + ")"
+			}
+		}
+		v, err := ParseValue(typ, s)
+		if err != nil {
+			return Model{}, fmt.Errorf("args[%d](%q): %w", i, key, err)
+		}
+		d = append(d, Entry{Key: key, Value: v})
+	}
+	return Model{Value: d}, nil
+}
+
+// InferValue returns the most specific value represented by s, in order of
+// precedence:
+//
+//   - "true" or "false" is a Bool.
+//   - A finite number, such as "3" or "-0.5", is a Double. The Int type is
+//     not implemented, so integers are also Doubles, which is how Roblox
+//     represents numbers.
+//   - Three finite numbers separated by commas, such as "1, 2, 3", are a
+//     Vector3.
+//   - Anything else is a String, including "NaN" and "Inf".
+func InferValue(s string) Value {
+	switch s {
+	case "true", "false":
+		v := ValueBool(s == "true")
+		return &v
+	}
+	if f, ok := parseFinite(s, 64); ok {
+		v := ValueDouble(f)
+		return &v
+	}
+	if parts := strings.Split(s, ","); len(parts) == 3 {
+		var c [3]float32
+		ok := true
+		for i, part := range parts {
+			var f float64
+			f, ok = parseFinite(strings.TrimSpace(part), 32)
+			if !ok {
+				break
+			}
+			c[i] = float32(f)
+		}
+		if ok {
+			return &ValueVector3{X: c[0], Y: c[1], Z: c[2]}
+		}
+	}
+	v := ValueString(s)
+	return &v
+}
+
+// parseFinite parses s as a number of the given bit size, and returns whether
+// it is a finite number.
+func parseFinite(s string, bitSize int) (float64, bool) {
+	f, err := strconv.ParseFloat(s, bitSize)
+	return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// typeFromName returns the implemented Type with the given name, compared
+// case-insensitively.
+func typeFromName(name string) (Type, bool) {
+	for typ, n := range typeNames {
+		if strings.EqualFold(n, name) && NewValue(typ) != nil {
+			return typ, true
+		}
+	}
+	return 0, false
+}
+
+// argParser parses the arguments of a structured value in order. The first
+// error is retained, after which parsing has no effect.
+type argParser struct {
+	typ  Type
+	args []string
+	i    int
+	err  error
+}
+
+// next returns the next argument, trimmed of spaces.
+func (p *argParser) next(field string) (string, bool) {
+	if p.err != nil {
+		return "", false
+	}
+	if p.i >= len(p.args) {
+		p.err = fmt.Errorf("%s.%s: missing argument", p.typ, field)
+		return "", false
+	}
+	arg := strings.TrimSpace(p.args[p.i])
+	p.i++
+	return arg, true
+}
+
+func (p *argParser) float(field string) float32 {
+	arg, ok := p.next(field)
+	if !ok {
+		return 0
+	}
+	f, err := strconv.ParseFloat(arg, 32)
+	if err != nil {
+		p.err = fmt.Errorf("%s.%s: invalid number %q", p.typ, field, arg)
+	}
+	return float32(f)
+}
+
+func (p *argParser) int32(field string) int32 {
+	arg, ok := p.next(field)
+	if !ok {
+		return 0
+	}
+	i, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil {
+		p.err = fmt.Errorf("%s.%s: invalid integer %q", p.typ, field, arg)
+	}
+	return int32(i)
+}
+
+func (p *argParser) uint32(field string) uint32 {
+	arg, ok := p.next(field)
+	if !ok {
+		return 0
+	}
+	i, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		p.err = fmt.Errorf("%s.%s: invalid integer %q", p.typ, field, arg)
+	}
+	return uint32(i)
+}
+
+func (p *argParser) color(field string) ValueColor3 {
+	arg, ok := p.next(field)
+	if !ok {
+		return ValueColor3{}
+	}
+	if len(arg) != 7 || arg[0] != '#' {
+		p.err = fmt.Errorf("%s.%s: expected #RRGGBB, got %q", p.typ, field, arg)
+		return ValueColor3{}
+	}
+	c, err := strconv.ParseUint(arg[1:], 16, 32)
+	if err != nil {
+		p.err = fmt.Errorf("%s.%s: expected #RRGGBB, got %q", p.typ, field, arg)
+		return ValueColor3{}
+	}
+	return ValueColor3{
+		R: float32(c>>16&0xFF) / 255,
+		G: float32(c>>8&0xFF) / 255,
+		B: float32(c&0xFF) / 255,
+	}
+}
+
+// end returns the first error, or an error if arguments remain.
+func (p *argParser) end() error {
+	if p.err == nil && p.i < len(p.args) {
+		p.err = fmt.Errorf("%s: expected %d arguments, got %d", p.typ, p.i, len(p.args))
+	}
+	return p.err
+}
+
+// valueFromArgs returns a structured Value of the given Type from its
+// components, in the order formatted by FormatValue.
+func valueFromArgs(typ Type, args []string) (Value, error) {
+	p := &argParser{typ: typ, args: args}
+	var v Value
+	switch typ {
+	case TypeUDim:
+		v = &ValueUDim{Scale: p.float("Scale"), Offset: p.int32("Offset")}
+	case TypeUDim2:
+		v = &ValueUDim2{
+			X: ValueUDim{Scale: p.float("X.Scale"), Offset: p.int32("X.Offset")},
+			Y: ValueUDim{Scale: p.float("Y.Scale"), Offset: p.int32("Y.Offset")},
+		}
+	case TypeBrickColor:
+		c := ValueBrickColor(p.uint32("Number"))
+		v = &c
+	case TypeColor3:
+		c := p.color("Color")
+		v = &c
+	case TypeVector2:
+		v = &ValueVector2{X: p.float("X"), Y: p.float("Y")}
+	case TypeVector3:
+		v = &ValueVector3{X: p.float("X"), Y: p.float("Y"), Z: p.float("Z")}
+	case TypeCFrame:
+		var c ValueCFrame
+		c.Position = ValueVector3{X: p.float("Position.X"), Y: p.float("Position.Y"), Z: p.float("Position.Z")}
+		for i := range c.Rotation {
+			c.Rotation[i] = p.float(fmt.Sprintf("Rotation[%d]", i))
+		}
+		v = &c
+	case TypeNumberRange:
+		v = &ValueNumberRange{Min: p.float("Min"), Max: p.float("Max")}
+	case TypeRect:
+		v = &ValueRect{
+			Min: ValueVector2{X: p.float("Min.X"), Y: p.float("Min.Y")},
+			Max: ValueVector2{X: p.float("Max.X"), Y: p.float("Max.Y")},
+		}
+	default:
+		return nil, fmt.Errorf("cannot parse value of type %s", typ)
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// MarshalText implements encoding.TextMarshaler, using the form of FormatValue.
+func (v ValueString) MarshalText() ([]byte, error) {
+	return []byte(v), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the form of
+// ParseValue.
+func (v *ValueString) UnmarshalText(text []byte) error {
+	*v = ValueString(text)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, using the form of FormatValue.
+func (v ValueBool) MarshalText() ([]byte, error) {
+	return []byte(FormatValue(&v)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the form of
+// ParseValue.
+func (v *ValueBool) UnmarshalText(text []byte) error {
+	p, err := ParseValue(TypeBool, string(text))
+	if err != nil {
+		return err
+	}
+	*v = *p.(*ValueBool)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, using the form of FormatValue.
+func (v ValueFloat) MarshalText() ([]byte, error) {
+	return []byte(FormatValue(&v)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the form of
+// ParseValue.
+func (v *ValueFloat) UnmarshalText(text []byte) error {
+	p, err := ParseValue(TypeFloat, string(text))
+	if err != nil {
+		return err
+	}
+	*v = *p.(*ValueFloat)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, using the form of FormatValue.
+func (v ValueDouble) MarshalText() ([]byte, error) {
+	return []byte(FormatValue(&v)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the form of
+// ParseValue.
+func (v *ValueDouble) UnmarshalText(text []byte) error {
+	p, err := ParseValue(TypeDouble, string(text))
+	if err != nil {
+		return err
+	}
+	*v = *p.(*ValueDouble)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, using the form of FormatValue.
+func (v ValueBrickColor) MarshalText() ([]byte, error) {
+	return []byte(FormatValue(&v)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the form of
+// ParseValue.
+func (v *ValueBrickColor) UnmarshalText(text []byte) error {
+	p, err := ParseValue(TypeBrickColor, string(text))
+	if err != nil {
+		return err
+	}
+	*v = *p.(*ValueBrickColor)
+	return nil
+}
@@ -0,0 +1,258 @@
+package rbxattr_test
+
+import (
+	"encoding"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestFormatValue(t *testing.T) {
+	s := rbxattr.ValueString("foo bar")
+	b := rbxattr.ValueBool(true)
+	f := rbxattr.ValueFloat(0.1)
+	d := rbxattr.ValueDouble(0.1)
+	brick := rbxattr.ValueBrickColor(194)
+	for _, c := range []struct {
+		value rbxattr.Value
+		want  string
+	}{
+		{&s, "foo bar"},
+		{&b, "true"},
+		{&f, "0.1"},
+		{&d, "0.1"},
+		{&brick, "BrickColor(194)"},
+		{&rbxattr.ValueUDim2{
+			X: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+			Y: rbxattr.ValueUDim{Scale: 0.5, Offset: -100},
+		}, "UDim2(0.5, 100, 0.5, -100)"},
+		{&rbxattr.ValueColor3{R: 1, G: 0.5, B: 0}, "Color3(#FF8000)"},
+		{&rbxattr.ValueVector3{X: 1, Y: 2.5, Z: -3}, "Vector3(1, 2.5, -3)"},
+		{&rbxattr.ValueCFrame{
+			Position: rbxattr.ValueVector3{X: 1, Y: 2, Z: 3},
+			Rotation: [9]float32{1, 0, 0, 0, 1, 0, 0, 0, 1},
+		}, "CFrame(1, 2, 3, 1, 0, 0, 0, 1, 0, 0, 0, 1)"},
+		{&rbxattr.ValueNumberSequence{{Time: 0}, {Time: 0.5}, {Time: 1}}, "NumberSequence[3]"},
+		{&rbxattr.ValueColorSequence{}, "ColorSequence[0]"},
+		{&rbxattr.ValueFont{
+			Weight:       700,
+			Style:        1,
+			Family:       "rbxasset://fonts/families/SourceSansPro.json",
+			CachedFaceID: "rbxasset://fonts/SourceSansPro-BoldItalic.ttf",
+		}, "Font(rbxasset://fonts/families/SourceSansPro.json, 700, 1)"},
+		{&rbxattr.ValueNumberRange{Min: 0, Max: 10}, "NumberRange(0, 10)"},
+		{&rbxattr.ValueRect{Max: rbxattr.ValueVector2{X: 1, Y: 1}}, "Rect(0, 0, 1, 1)"},
+	} {
+		if got := rbxattr.FormatValue(c.value); got != c.want {
+			t.Errorf("%s: expected %q, got %q", c.value.Type(), c.want, got)
+		}
+	}
+}
+
+func TestFormatValueDictionary(t *testing.T) {
+	// Values cannot nest, so a dictionary is summarized one entry at a time.
+	s := rbxattr.ValueString("foo bar")
+	b := rbxattr.ValueBool(true)
+	dict := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Enabled", Value: &b},
+		{Key: "Fade", Value: &rbxattr.ValueNumberSequence{{Time: 0}, {Time: 1}}},
+		{Key: "Missing", Value: nil},
+	}
+	var lines []string
+	for _, entry := range dict {
+		lines = append(lines, entry.Key+"="+rbxattr.FormatValue(entry.Value))
+	}
+	const want = "Name=foo bar, Enabled=true, Fade=NumberSequence[2], Missing=nil"
+	if got := strings.Join(lines, ", "); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	for _, v := range []rbxattr.Value{
+		&rbxattr.ValueUDim2{
+			X: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+			Y: rbxattr.ValueUDim{Scale: -0.25, Offset: -100},
+		},
+		&rbxattr.ValueColor3{R: 1, G: 128.0 / 255, B: 0},
+		&rbxattr.ValueCFrame{
+			Position: rbxattr.ValueVector3{X: 1, Y: 2, Z: 3},
+			Rotation: [9]float32{0, -1, 0, 1, 0, 0, 0, 0, 1},
+		},
+	} {
+		s := rbxattr.FormatValue(v)
+		parsed, err := rbxattr.ParseValue(v.Type(), s)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", s, err)
+			continue
+		}
+		if !(rbxattr.ValueDictionary{{Value: parsed}}).Equal(rbxattr.ValueDictionary{{Value: v}}) {
+			t.Errorf("%s: parsed value %s does not match", s, rbxattr.FormatValue(parsed))
+		}
+	}
+
+	for _, c := range []struct {
+		typ  rbxattr.Type
+		s    string
+		want string
+	}{
+		{rbxattr.TypeBool, "yes", `Bool: invalid value "yes"`},
+		{rbxattr.TypeUDim2, "UDim(0, 0)", `UDim2: expected UDim2(...), got "UDim(0, 0)"`},
+		{rbxattr.TypeUDim2, "UDim2(0.5, 100, 0.5)", "UDim2.Y.Offset: missing argument"},
+		{rbxattr.TypeUDim2, "UDim2(0.5, 1.5, 0.5, 100)", `UDim2.X.Offset: invalid integer "1.5"`},
+		{rbxattr.TypeVector2, "Vector2(1, 2, 3)", "Vector2: expected 2 arguments, got 3"},
+		{rbxattr.TypeColor3, "Color3(#FF80)", `Color3.Color: expected #RRGGBB, got "#FF80"`},
+		{rbxattr.TypeNumberSequence, "NumberSequence[3]", "cannot parse value of type NumberSequence"},
+	} {
+		if _, err := rbxattr.ParseValue(c.typ, c.s); err == nil {
+			t.Errorf("%q: expected error", c.s)
+		} else if err.Error() != c.want {
+			t.Errorf("%q: expected error %q, got %q", c.s, c.want, err)
+		}
+	}
+}
+
+func TestValueMarshalText(t *testing.T) {
+	s := rbxattr.ValueString("foo bar")
+	b := rbxattr.ValueBool(true)
+	f := rbxattr.ValueFloat(0.1)
+	d := rbxattr.ValueDouble(-1e100)
+	brick := rbxattr.ValueBrickColor(194)
+	for _, c := range []struct {
+		value interface {
+			encoding.TextMarshaler
+			encoding.TextUnmarshaler
+		}
+		zero interface {
+			encoding.TextUnmarshaler
+			rbxattr.Value
+		}
+		want string
+	}{
+		{&s, new(rbxattr.ValueString), "foo bar"},
+		{&b, new(rbxattr.ValueBool), "true"},
+		{&f, new(rbxattr.ValueFloat), "0.1"},
+		{&d, new(rbxattr.ValueDouble), "-1e+100"},
+		{&brick, new(rbxattr.ValueBrickColor), "BrickColor(194)"},
+	} {
+		text, err := c.value.MarshalText()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.zero.Type(), err)
+			continue
+		}
+		if string(text) != c.want {
+			t.Errorf("%s: expected %q, got %q", c.zero.Type(), c.want, text)
+		}
+		if err := c.zero.UnmarshalText(text); err != nil {
+			t.Errorf("%s: unexpected error: %s", c.zero.Type(), err)
+			continue
+		}
+		if !(rbxattr.ValueDictionary{{Value: c.zero}}).Equal(rbxattr.ValueDictionary{{Value: c.value.(rbxattr.Value)}}) {
+			t.Errorf("%s: round trip produced %s", c.zero.Type(), rbxattr.FormatValue(c.zero))
+		}
+	}
+
+	var v rbxattr.ValueFloat
+	if err := v.UnmarshalText([]byte("abc")); err == nil {
+		t.Error("expected error for invalid Float")
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	model, err := rbxattr.ParseArgs([]string{
+		"Name=Hello=World",
+		"Enabled:bool=true",
+		"Scale:Float=0.5",
+		"Count:double=3.14",
+		"Size:udim2=0.5,100,0.5,-100",
+		"Pos:vector3=1,2,3",
+		"Offset:Vector2=Vector2(4, 5)",
+		"Tint:Color3=#FF8000",
+		"Brick:BrickColor=194",
+		"Empty:string=",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := rbxattr.ValueString("Hello=World")
+	enabled := rbxattr.ValueBool(true)
+	scale := rbxattr.ValueFloat(0.5)
+	count := rbxattr.ValueDouble(3.14)
+	brick := rbxattr.ValueBrickColor(194)
+	empty := rbxattr.ValueString("")
+	want := rbxattr.ValueDictionary{
+		{Key: "Name", Value: &name},
+		{Key: "Enabled", Value: &enabled},
+		{Key: "Scale", Value: &scale},
+		{Key: "Count", Value: &count},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{
+			X: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+			Y: rbxattr.ValueUDim{Scale: 0.5, Offset: -100},
+		}},
+		{Key: "Pos", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+		{Key: "Offset", Value: &rbxattr.ValueVector2{X: 4, Y: 5}},
+		{Key: "Tint", Value: &rbxattr.ValueColor3{R: 1, G: 128.0 / 255, B: 0}},
+		{Key: "Brick", Value: &brick},
+		{Key: "Empty", Value: &empty},
+	}
+	if !model.Value.Equal(want) {
+		t.Fatal("parsed model does not match")
+	}
+
+	for _, c := range []struct {
+		arg  string
+		want string
+	}{
+		{"Name", `args[0]: expected Key=Value, got "Name"`},
+		{"=Hello", "args[0]: empty key"},
+		{":bool=true", "args[0]: empty key"},
+		{"Count:int=3", `args[0]: unknown type "int"`},
+		{"Pos:vector3=1,2", `args[0]("Pos"): Vector3.Z: missing argument`},
+		{"Enabled:bool=maybe", `args[0]("Enabled"): Bool: invalid value "maybe"`},
+		{"Seq:NumberSequence=1", `args[0]("Seq"): cannot parse value of type NumberSequence`},
+	} {
+		if _, err := rbxattr.ParseArgs([]string{c.arg}); err == nil {
+			t.Errorf("%q: expected error", c.arg)
+		} else if err.Error() != c.want {
+			t.Errorf("%q: expected error %q, got %q", c.arg, c.want, err)
+		}
+	}
+}
+
+func TestInferValue(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want string
+	}{
+		{"true", "Bool true"},
+		{"false", "Bool false"},
+		{"True", `String "True"`},
+		{"3", "Double 3"},
+		{"-0.5", "Double -0.5"},
+		{"1e3", "Double 1000"},
+		{"NaN", `String "NaN"`},
+		{"Inf", `String "Inf"`},
+		{"1,2,3", "Vector3(1, 2, 3)"},
+		{"1.5, -2, 3e2", "Vector3(1.5, -2, 300)"},
+		{"1,2", `String "1,2"`},
+		{"1,2,x", `String "1,2,x"`},
+		{"1,2,1e40", `String "1,2,1e40"`},
+		{"", `String ""`},
+		{"Hello", `String "Hello"`},
+	} {
+		v := rbxattr.InferValue(c.s)
+		got := rbxattr.FormatValue(v)
+		switch v.(type) {
+		case *rbxattr.ValueString:
+			got = fmt.Sprintf("%s %q", v.Type(), got)
+		case *rbxattr.ValueBool, *rbxattr.ValueDouble:
+			got = v.Type().String() + " " + got
+		}
+		if got != c.want {
+			t.Errorf("%q: expected %s, got %s", c.s, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package rbxattr
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var (
+	cframeType    = reflect.TypeOf(ValueCFrame{})
+	floatWideType = reflect.TypeOf(ValueFloatWide(0))
+)
+
+// tracer writes a human-readable description of each field of an encoded
+// value, along with the offset at which the field was encoded.
+type tracer struct {
+	w      io.Writer
+	offset int64
+}
+
+// field describes a field of the given size, and advances the offset.
+func (t *tracer) field(path string, value interface{}, size int64) {
+	fmt.Fprintf(t.w, "wrote %s=%v at offset %d\n", path, value, t.offset)
+	t.offset += size
+}
+
+// entry describes the i-th entry of a dictionary.
+func (t *tracer) entry(i int, e Entry) {
+	t.field(fmt.Sprintf("Dictionary[%d].Key", i), fmt.Sprintf("%q", e.Key), 4+int64(len(e.Key)))
+	t.field(fmt.Sprintf("Dictionary[%d].Type", i), e.Value.Type(), 1)
+	t.value(e.Value.Type().String(), reflect.ValueOf(e.Value))
+}
+
+// value describes each field of rv, which has the given path.
+func (t *tracer) value(path string, rv reflect.Value) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			t.field(path, nil, 0)
+			return
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		if rv.Type() == cframeType {
+			var rotation [9]float32
+			for i := range rotation {
+				rotation[i] = float32(rv.FieldByName("Rotation").Index(i).Float())
+			}
+			t.value(path+".Position", rv.FieldByName("Position"))
+			id := cframeIDNumber[rotation]
+			t.field(path+".ID", id, 1)
+			if id == 0 {
+				t.value(path+".Rotation", rv.FieldByName("Rotation"))
+			}
+			return
+		}
+		for i := 0; i < rv.NumField(); i++ {
+			t.value(path+"."+rv.Type().Field(i).Name, rv.Field(i))
+		}
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			t.value(fmt.Sprintf("%s[%d]", path, i), rv.Index(i))
+		}
+	case reflect.Slice:
+		if rv.Type().ConvertibleTo(bytesType) {
+			t.field(path, fmt.Sprintf("%q", rv.Bytes()), 4+int64(rv.Len()))
+			return
+		}
+		t.field(path+".Length", rv.Len(), 4)
+		for i := 0; i < rv.Len(); i++ {
+			t.value(fmt.Sprintf("%s[%d]", path, i), rv.Index(i))
+		}
+	case reflect.String:
+		t.field(path, fmt.Sprintf("%q", rv.String()), 4+int64(rv.Len()))
+	default:
+		if rv.Type() == floatWideType {
+			// Encoded as a float32.
+			t.field(path, leafValue(rv), 4)
+			return
+		}
+		t.field(path, leafValue(rv), int64(rv.Type().Size()))
+	}
+}
+
+// leafValue returns the content of rv for display. Unlike Interface, it does
+// not panic if rv was obtained through an unexported field, as may occur with
+// a Value implemented outside of the package.
+func leafValue(rv reflect.Value) interface{} {
+	if rv.CanInterface() {
+		return rv.Interface()
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32:
+		return float32(rv.Float())
+	case reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	}
+	return "<" + rv.Type().String() + ">"
+}
@@ -0,0 +1,229 @@
+package rbxattr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Validate reads an encoded Model from r, and returns an error if it is not
+// well-formed. Every length prefix and type is checked, and the stream must end
+// immediately after the last entry. Unlike Model.ReadFrom, no values are
+// constructed, making Validate a cheap check of untrusted input. Validate does
+// not check whether the content of values would be accepted by Roblox; see
+// Model.Validate.
+func Validate(r io.Reader) error {
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return fmt.Errorf("format: Dictionary length: %w", br.Err())
+	}
+	for i := 0; i < int(length); i++ {
+		var keyLength uint32
+		if br.Number(&keyLength) || br.Skip(int64(keyLength)) {
+			return fmt.Errorf("format: Dictionary[%d] key: %w", i, br.Err())
+		}
+		var typ byte
+		if br.Number(&typ) {
+			return fmt.Errorf("format: Dictionary[%d] type: %w", i, br.Err())
+		}
+		if br.Add(skipValue(r, Type(typ))) {
+			return fmt.Errorf("format: Dictionary[%d] value: %w", i, br.Err())
+		}
+	}
+	var b [1]byte
+	if n, err := io.ReadFull(r, b[:]); n > 0 {
+		return fmt.Errorf("format: trailing data at offset %d", br.N())
+	} else if err != io.EOF {
+		return fmt.Errorf("format: %w", err)
+	}
+	return nil
+}
+
+// CheckRoundTrip decodes a Model from b, encodes it again, and returns an error
+// if the encoded bytes are not identical to b. The error reports the offset of
+// the first byte that differs. Data that decodes successfully may still fail
+// the check if it is not in the form produced by the encoder, such as a CFrame
+// with an explicit rotation matrix that has a special ID, or trailing data.
+func CheckRoundTrip(b []byte) error {
+	var m Model
+	if _, err := m.ReadFrom(bytes.NewReader(b)); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		return err
+	}
+	out := buf.Bytes()
+	i := 0
+	for i < len(b) && i < len(out) && b[i] == out[i] {
+		i++
+	}
+	switch {
+	case i < len(b) && i < len(out):
+		return fmt.Errorf("format: round trip: differs at offset %d: read 0x%02X, wrote 0x%02X", i, b[i], out[i])
+	case len(b) != len(out):
+		return fmt.Errorf("format: round trip: differs at offset %d: read %d bytes, wrote %d", i, len(b), len(out))
+	}
+	return nil
+}
+
+// Validate returns an error if the model contains an entry that Roblox would
+// reject. The error describes the first such entry.
+//
+// A Float that is infinite is reported, as it most likely resulted from
+// narrowing a value beyond the range of float32. Such values should be stored
+// as a Double instead. A Double that is infinite is not reported.
+func (f Model) Validate() error {
+	for i, entry := range f.Value {
+		if entry.Key == "" {
+			return fmt.Errorf("Dictionary[%d](%q) key: empty key", i, entry.Key)
+		}
+		if err := validateValue(entry.Value); err != nil {
+			return fmt.Errorf("Dictionary[%d](%q) value: %w", i, entry.Key, err)
+		}
+	}
+	return nil
+}
+
+// SchemaError is returned by Model.ValidateAgainst when a model does not
+// conform to a schema.
+type SchemaError struct {
+	// Missing contains each key of the schema that is not in the model,
+	// sorted.
+	Missing []string
+	// Extra contains each key of the model that is not in the schema, sorted.
+	Extra []string
+	// Mismatched maps each key whose value does not have the type of the
+	// schema to the name of the expected type followed by the name of the
+	// actual type.
+	Mismatched map[string][2]string
+}
+
+func (err *SchemaError) Error() string {
+	var parts []string
+	if len(err.Missing) > 0 {
+		parts = append(parts, "missing keys "+strings.Join(err.Missing, ", "))
+	}
+	if len(err.Extra) > 0 {
+		parts = append(parts, "extra keys "+strings.Join(err.Extra, ", "))
+	}
+	if len(err.Mismatched) > 0 {
+		keys := make([]string, 0, len(err.Mismatched))
+		for key := range err.Mismatched {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			types := err.Mismatched[key]
+			keys[i] = fmt.Sprintf("%s (expected %s, got %s)", key, types[0], types[1])
+		}
+		parts = append(parts, "mismatched types "+strings.Join(keys, ", "))
+	}
+	return "schema: " + strings.Join(parts, "; ")
+}
+
+// ValidateAgainst returns a *SchemaError if the model does not match schema,
+// which maps each expected key to the name of the type of its value, as
+// returned by Schema. The error reports every key that is missing, extra, or
+// has a value of the wrong type. Only the first entry of each key is
+// considered.
+func (f Model) ValidateAgainst(schema map[string]string) error {
+	err := &SchemaError{Mismatched: map[string][2]string{}}
+	actual := f.Schema()
+	for key, typ := range schema {
+		if got, ok := actual[key]; !ok {
+			err.Missing = append(err.Missing, key)
+		} else if got != typ {
+			err.Mismatched[key] = [2]string{typ, got}
+		}
+	}
+	for key := range actual {
+		if _, ok := schema[key]; !ok {
+			err.Extra = append(err.Extra, key)
+		}
+	}
+	if len(err.Missing) == 0 && len(err.Extra) == 0 && len(err.Mismatched) == 0 {
+		return nil
+	}
+	sort.Strings(err.Missing)
+	sort.Strings(err.Extra)
+	return err
+}
+
+// validateValue returns an error if v is a value that Roblox would reject.
+func validateValue(v Value) error {
+	switch v := v.(type) {
+	case *ValueFloat:
+		if math.IsInf(float64(*v), 0) {
+			return fmt.Errorf("Float: infinite value %v overflows float32", float64(*v))
+		}
+	case *ValueFloatWide:
+		if f := narrowFloat(float64(*v)); math.IsInf(float64(f), 0) {
+			return fmt.Errorf("Float: infinite value %v overflows float32", float64(*v))
+		}
+	case *ValueUDim:
+		if !v.Valid() {
+			return fmt.Errorf("UDim: invalid scale %v", v.Scale)
+		}
+	case *ValueUDim2:
+		if !v.X.Valid() {
+			return fmt.Errorf("UDim2.X: invalid scale %v", v.X.Scale)
+		}
+		if !v.Y.Valid() {
+			return fmt.Errorf("UDim2.Y: invalid scale %v", v.Y.Scale)
+		}
+	}
+	return nil
+}
+
+// AuditPrecision reports groups of attributes that mix Float and Double
+// values, which most likely indicates that a single logical value, such as a
+// position stored as one attribute per component, was stored inconsistently.
+// Attributes are grouped by the part of their key before the last "." or "_",
+// so "Pos.X" and "Pos.Y" are in the group "Pos". Only the first entry of each
+// key is considered. Each report describes one group, and reports are sorted
+// by group. The result is advisory, and not an error.
+func (f Model) AuditPrecision() []string {
+	type member struct {
+		key string
+		typ Type
+	}
+	groups := map[string][]member{}
+	idx := f.Value.Index()
+	for i, entry := range f.Value {
+		if idx[entry.Key] != i {
+			continue
+		}
+		typ := entry.Value.Type()
+		if typ != TypeFloat && typ != TypeDouble {
+			continue
+		}
+		j := strings.LastIndexAny(entry.Key, "._")
+		if j <= 0 {
+			continue
+		}
+		group := entry.Key[:j]
+		groups[group] = append(groups[group], member{key: entry.Key, typ: typ})
+	}
+	var reports []string
+	for group, members := range groups {
+		mixed := false
+		for _, m := range members {
+			mixed = mixed || m.typ != members[0].typ
+		}
+		if !mixed {
+			continue
+		}
+		parts := make([]string, len(members))
+		for i, m := range members {
+			parts[i] = fmt.Sprintf("%s is %s", m.key, m.typ)
+		}
+		reports = append(reports, fmt.Sprintf("%s: mixed precision: %s", group, strings.Join(parts, ", ")))
+	}
+	sort.Strings(reports)
+	return reports
+}
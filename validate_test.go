@@ -0,0 +1,258 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+func TestUDimValid(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	for _, c := range []struct {
+		udim  rbxattr.ValueUDim
+		valid bool
+	}{
+		{rbxattr.ValueUDim{Scale: 0.5, Offset: -50}, true},
+		{rbxattr.ValueUDim{Scale: 0, Offset: math.MaxInt32}, true},
+		{rbxattr.ValueUDim{Scale: 0, Offset: math.MinInt32}, true},
+		{rbxattr.ValueUDim{Scale: math.MaxFloat32, Offset: 0}, true},
+		{rbxattr.ValueUDim{Scale: nan, Offset: 0}, false},
+		{rbxattr.ValueUDim{Scale: inf, Offset: 0}, false},
+		{rbxattr.ValueUDim{Scale: -inf, Offset: 0}, false},
+	} {
+		if got := c.udim.Valid(); got != c.valid {
+			t.Errorf("%v: expected %t, got %t", c.udim, c.valid, got)
+		}
+	}
+}
+
+func TestModelValidateUDim(t *testing.T) {
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Padding", Value: &rbxattr.ValueUDim{Scale: 0, Offset: math.MinInt32}},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{
+			X: rbxattr.ValueUDim{Scale: 0.5, Offset: math.MaxInt32},
+		}},
+	}}
+	if err := model.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	model.Value[1].Value.(*rbxattr.ValueUDim2).Y.Scale = float32(math.NaN())
+	err := model.Validate()
+	if err == nil {
+		t.Fatal("expected error for NaN scale")
+	}
+	const msg = `Dictionary[1]("Size") value: UDim2.Y: invalid scale NaN`
+	if err.Error() != msg {
+		t.Fatalf("unexpected error message %q", err)
+	}
+}
+
+func TestModelValidateFloat(t *testing.T) {
+	big := 1e39
+	f := rbxattr.ValueFloat(big)
+	d := rbxattr.ValueDouble(big)
+	inf := rbxattr.ValueDouble(math.Inf(-1))
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Double", Value: &d},
+		{Key: "Infinite", Value: &inf},
+		{Key: "Float", Value: &f},
+	}}
+	err := model.Validate()
+	if err == nil {
+		t.Fatal("expected error for overflowing float")
+	}
+	const msg = `Dictionary[2]("Float") value: Float: infinite value +Inf overflows float32`
+	if err.Error() != msg {
+		t.Fatalf("unexpected error message %q", err)
+	}
+
+	f = rbxattr.ValueFloat(math.MaxFloat32)
+	if err := model.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestModelValidateEmptyKey(t *testing.T) {
+	b := rbxattr.ValueBool(true)
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Enabled", Value: &b},
+		{Key: "Visible", Value: &b},
+	}}
+	if err := model.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	model.Value[1].Key = ""
+	err := model.Validate()
+	if err == nil {
+		t.Fatal("expected error for empty key")
+	}
+	const msg = `Dictionary[1]("") key: empty key`
+	if err.Error() != msg {
+		t.Fatalf("unexpected error message %q", err)
+	}
+}
+
+func TestValidateStream(t *testing.T) {
+	files, err := ioutil.ReadDir("testdata/corpus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		b, err := ioutil.ReadFile(filepath.Join("testdata/corpus", file.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rbxattr.Validate(bytes.NewReader(b)); err != nil {
+			t.Errorf("%s: unexpected error: %s", file.Name(), err)
+		}
+	}
+
+	b, err := ioutil.ReadFile("testdata/corpus/sample.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every truncation must fail, exactly as decoding does.
+	for i := 0; i < len(b); i++ {
+		var model rbxattr.Model
+		_, rerr := model.ReadFrom(bytes.NewReader(b[:i]))
+		verr := rbxattr.Validate(bytes.NewReader(b[:i]))
+		if verr == nil || rerr == nil {
+			t.Errorf("truncated to %d: expected errors, got %v, %v", i, verr, rerr)
+		}
+	}
+	corrupt := append([]byte(nil), b...)
+	corrupt[12] = 0x1F
+	if err := rbxattr.Validate(bytes.NewReader(corrupt)); err == nil {
+		t.Error("corrupt type: expected error")
+	} else if want := `format: Dictionary[0] value: unknown data type 0x1F`; err.Error() != want {
+		t.Errorf("corrupt type: expected error %q, got %q", want, err)
+	}
+	if err := rbxattr.Validate(bytes.NewReader(append(b[:len(b):len(b)], 0))); err == nil {
+		t.Error("trailing data: expected error")
+	}
+}
+
+func TestModelAuditPrecision(t *testing.T) {
+	x := rbxattr.ValueFloat(1)
+	y := rbxattr.ValueDouble(2)
+	z := rbxattr.ValueFloat(3)
+	w := rbxattr.ValueDouble(4)
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Pos.X", Value: &x},
+		{Key: "Pos.Y", Value: &y},
+		{Key: "Pos.Z", Value: &z},
+		{Key: "Size_X", Value: &x},
+		{Key: "Size_Y", Value: &x},
+		{Key: "Scale", Value: &w},
+		{Key: "Speed", Value: &x},
+	}}
+	want := []string{"Pos: mixed precision: Pos.X is Float, Pos.Y is Double, Pos.Z is Float"}
+	got := model.AuditPrecision()
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %q, got %q", want[i], got[i])
+		}
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/corpus/sample.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rbxattr.CheckRoundTrip(b); err != nil {
+		t.Errorf("sample: unexpected error: %s", err)
+	}
+
+	// Unusual, but stable: duplicate and empty keys, negative zero, and a NaN
+	// with a payload.
+	negZero := rbxattr.ValueFloat(math.Copysign(0, -1))
+	nan := rbxattr.ValueDouble(math.Float64frombits(0x7FF8000000000123))
+	s := rbxattr.ValueString("")
+	unusual := encode(t, rbxattr.ValueDictionary{
+		{Key: "", Value: &s},
+		{Key: "Value", Value: &negZero},
+		{Key: "Value", Value: &nan},
+	})
+	if err := rbxattr.CheckRoundTrip(unusual); err != nil {
+		t.Errorf("unusual: unexpected error: %s", err)
+	}
+
+	// A CFrame with an explicit identity matrix is re-encoded with a special
+	// ID.
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(1))
+	binary.Write(&buf, binary.LittleEndian, uint32(2))
+	buf.WriteString("CF")
+	buf.WriteByte(byte(rbxattr.TypeCFrame))
+	binary.Write(&buf, binary.LittleEndian, [3]float32{})
+	buf.WriteByte(0)
+	binary.Write(&buf, binary.LittleEndian, [9]float32{1, 0, 0, 0, 1, 0, 0, 0, 1})
+	const want = "format: round trip: differs at offset 23: read 0x00, wrote 0x02"
+	if err := rbxattr.CheckRoundTrip(buf.Bytes()); err == nil || err.Error() != want {
+		t.Errorf("explicit identity: expected error %q, got %v", want, err)
+	}
+
+	const trailing = "format: round trip: differs at offset 4: read 5 bytes, wrote 4"
+	if err := rbxattr.CheckRoundTrip([]byte{0, 0, 0, 0, 0}); err == nil || err.Error() != trailing {
+		t.Errorf("trailing data: expected error %q, got %v", trailing, err)
+	}
+	if err := rbxattr.CheckRoundTrip(b[:len(b)-1]); err == nil {
+		t.Error("truncated: expected error")
+	}
+}
+
+func TestModelValidateAgainst(t *testing.T) {
+	s := rbxattr.ValueString("foo")
+	f := rbxattr.ValueFloat(1)
+	model := rbxattr.Model{Value: rbxattr.ValueDictionary{
+		{Key: "Name", Value: &s},
+		{Key: "Scale", Value: &f},
+		{Key: "Size", Value: &rbxattr.ValueUDim2{}},
+	}}
+	schema := map[string]string{
+		"Name":  "String",
+		"Scale": "Float",
+		"Size":  "UDim2",
+	}
+	if err := model.ValidateAgainst(schema); err != nil {
+		t.Fatalf("conforming model: unexpected error: %s", err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		schema map[string]string
+		want   string
+	}{
+		{"missing", map[string]string{"Name": "String", "Scale": "Float", "Size": "UDim2", "Speed": "Double", "Color": "Color3"},
+			"schema: missing keys Color, Speed"},
+		{"extra", map[string]string{"Name": "String"},
+			"schema: extra keys Scale, Size"},
+		{"mismatched", map[string]string{"Name": "String", "Scale": "Double", "Size": "UDim2"},
+			"schema: mismatched types Scale (expected Double, got Float)"},
+		{"all", map[string]string{"Name": "Bool", "Scale": "Float", "Speed": "Double"},
+			"schema: missing keys Speed; extra keys Size; mismatched types Name (expected Bool, got String)"},
+	} {
+		err := model.ValidateAgainst(c.schema)
+		var serr *rbxattr.SchemaError
+		if !errors.As(err, &serr) {
+			t.Errorf("%s: expected SchemaError, got %v", c.name, err)
+			continue
+		}
+		if err.Error() != c.want {
+			t.Errorf("%s: expected error %q, got %q", c.name, c.want, err)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package rbxattr
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"math"
+	"strings"
 )
 
 // Type identifies an attribute type within an encoding.
@@ -47,8 +49,53 @@ const (
 	_                  Type = 0x1E // Unknown
 	_                  Type = 0x1F // Region3
 	_                  Type = 0x20 // Region3int16
+	TypeFont           Type = 0x21
 )
 
+var typeNames = map[Type]string{
+	0x00:               "Null",
+	0x01:               "Empty",
+	TypeString:         "String",
+	TypeBool:           "Bool",
+	0x04:               "Int",
+	TypeFloat:          "Float",
+	TypeDouble:         "Double",
+	0x07:               "Array",
+	0x08:               "Dictionary",
+	TypeUDim:           "UDim",
+	TypeUDim2:          "UDim2",
+	0x0B:               "Ray",
+	0x0C:               "Faces",
+	0x0D:               "Axes",
+	TypeBrickColor:     "BrickColor",
+	TypeColor3:         "Color3",
+	TypeVector2:        "Vector2",
+	TypeVector3:        "Vector3",
+	0x12:               "Vector2int16",
+	0x13:               "Vector3int16",
+	TypeCFrame:         "CFrame",
+	0x15:               "EnumItem",
+	TypeNumberSequence: "NumberSequence",
+	0x18:               "NumberSequenceKeypoint",
+	TypeColorSequence:  "ColorSequence",
+	0x1A:               "ColorSequenceKeypoint",
+	TypeNumberRange:    "NumberRange",
+	TypeRect:           "Rect",
+	0x1D:               "PhysicalProperties",
+	0x1F:               "Region3",
+	0x20:               "Region3int16",
+	TypeFont:           "Font",
+}
+
+// String returns the name of the type, or the byte value in hexadecimal if the
+// type has no known name.
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02X", byte(t))
+}
+
 // Value is an attribute value that can be decoded from and encoded to bytes,
 // with an identifying type.
 type Value interface {
@@ -91,10 +138,43 @@ func NewValue(typ Type) Value {
 		return new(ValueNumberRange)
 	case TypeRect:
 		return new(ValueRect)
+	case TypeFont:
+		return new(ValueFont)
 	}
 	return nil
 }
 
+// UnknownTypeError indicates that a value had a type not known by NewValue.
+type UnknownTypeError struct {
+	// Types contains each distinct unknown type, in order of appearance.
+	Types []Type
+}
+
+// reservedTypes contains types that are reserved within the format, but whose
+// structure is not yet understood.
+var reservedTypes = map[Type]bool{
+	0x16: true,
+	0x1E: true,
+}
+
+func (err *UnknownTypeError) Error() string {
+	if len(err.Types) == 1 {
+		if reservedTypes[err.Types[0]] {
+			return fmt.Sprintf("reserved type 0x%02X not yet understood", byte(err.Types[0]))
+		}
+		return fmt.Sprintf("unknown data type 0x%02X", byte(err.Types[0]))
+	}
+	var b strings.Builder
+	b.WriteString("unknown data types ")
+	for i, typ := range err.Types {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "0x%02X", byte(typ))
+	}
+	return b.String()
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 // type ValueNull struct{}
@@ -131,6 +211,57 @@ func (v ValueString) WriteTo(w io.Writer) (n int64, err error) {
 
 ////////////////////////////////////////////////////////////////////////////////
 
+// ValueBytes is a String value with content held as bytes.
+//
+// When decoded from a *bytes.Buffer, the content is not copied, but instead
+// aliases the memory of the buffer. In that case, the content is valid only
+// until the buffer is next modified, and must not itself be modified.
+type ValueBytes []byte
+
+func (ValueBytes) Type() Type {
+	return TypeString
+}
+
+func (v *ValueBytes) ReadFrom(r io.Reader) (n int64, err error) {
+	br := newBinaryReader(r)
+	var length uint32
+	if br.Number(&length) {
+		return br.N(), fmt.Errorf("String: %w", br.Err())
+	}
+	if buf, ok := r.(*bytes.Buffer); ok {
+		if buf.Len() < int(length) {
+			n := buf.Len()
+			buf.Next(n)
+			err := io.ErrUnexpectedEOF
+			if n == 0 {
+				err = io.EOF
+			}
+			return br.N() + int64(n), fmt.Errorf("String: %w", err)
+		}
+		*v = buf.Next(int(length))
+		return br.N() + int64(length), nil
+	}
+	s := make([]byte, length)
+	if br.Bytes(s) {
+		return br.N(), fmt.Errorf("String: %w", br.Err())
+	}
+	*v = s
+	return br.End()
+}
+
+func (v ValueBytes) WriteTo(w io.Writer) (n int64, err error) {
+	bw := newBinaryWriter(w)
+	if bw.Number(uint32(len(v))) {
+		return bw.N(), fmt.Errorf("String: %w", bw.Err())
+	}
+	if bw.Bytes(v) {
+		return bw.N(), fmt.Errorf("String: %w", bw.Err())
+	}
+	return bw.End()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
 type ValueBool bool
 
 func (ValueBool) Type() Type {
@@ -147,12 +278,18 @@ func (v *ValueBool) ReadFrom(r io.Reader) (n int64, err error) {
 	return br.End()
 }
 
+// Encoded forms of a Bool, shared to avoid allocating for each write. Writers
+// must not modify these, per the contract of io.Writer.
+var boolFalse, boolTrue = []byte{0}, []byte{1}
+
 func (v ValueBool) WriteTo(w io.Writer) (n int64, err error) {
 	bw := newBinaryWriter(w)
+	b := boolFalse
 	if v {
-		bw.Number(byte(1))
-	} else {
-		bw.Number(byte(0))
+		b = boolTrue
+	}
+	if bw.Bytes(b) {
+		return bw.N(), fmt.Errorf("Bool: %w", bw.Err())
 	}
 	return bw.End()
 }
@@ -189,6 +326,62 @@ func (v ValueFloat) WriteTo(w io.Writer) (n int64, err error) {
 
 ////////////////////////////////////////////////////////////////////////////////
 
+// ValueFloatWide is a Float value with content held as a float64, avoiding
+// repeated narrowing when the value is used in arithmetic. It is produced by a
+// Decoder with the WideFloats option. The content is narrowed to float32 when
+// encoded, so precision gained by arithmetic is lost on re-encoding. A value
+// that was decoded and not modified re-encodes to identical bytes, including
+// the payload of a NaN.
+type ValueFloatWide float64
+
+func (ValueFloatWide) Type() Type {
+	return TypeFloat
+}
+
+func (v *ValueFloatWide) ReadFrom(r io.Reader) (n int64, err error) {
+	br := newBinaryReader(r)
+	var a float32
+	if br.Number(&a) {
+		return br.N(), fmt.Errorf("Float: %w", br.Err())
+	}
+	*v = ValueFloatWide(widenFloat(a))
+	return br.End()
+}
+
+func (v ValueFloatWide) WriteTo(w io.Writer) (n int64, err error) {
+	bw := newBinaryWriter(w)
+	if bw.Number(narrowFloat(float64(v))) {
+		return bw.N(), fmt.Errorf("Float: %w", bw.Err())
+	}
+	return bw.End()
+}
+
+// widenFloat converts f to a float64. Unlike a conversion, the sign and payload
+// of a NaN are preserved exactly.
+func widenFloat(f float32) float64 {
+	if f == f {
+		return float64(f)
+	}
+	b := math.Float32bits(f)
+	return math.Float64frombits(uint64(b>>31)<<63 | 0x7FF<<52 | uint64(b&0x7FFFFF)<<29)
+}
+
+// narrowFloat converts f to a float32, reversing widenFloat. A NaN whose
+// payload does not fit in a float32 remains a NaN.
+func narrowFloat(f float64) float32 {
+	if f == f {
+		return float32(f)
+	}
+	b := math.Float64bits(f)
+	m := uint32(b>>29) & 0x7FFFFF
+	if m == 0 {
+		m = 0x400000
+	}
+	return math.Float32frombits(uint32(b>>63)<<31 | 0xFF<<23 | m)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
 type ValueDouble float64
 
 func (ValueDouble) Type() Type {
@@ -224,6 +417,46 @@ type Entry struct {
 	Value Value
 }
 
+// readFrom decodes e as the i-th entry of a dictionary, using newValue to
+// create the value, and interning strings with pool if it is not nil. If an
+// error occurs after the key is decoded, then e.Key is set to the key.
+func (e *Entry) readFrom(r io.Reader, i int, newValue func(Type) Value, pool *stringPool) (n int64, err error) {
+	br := newBinaryReader(r)
+	var key string
+	if pool.String(br, &key) {
+		return br.N(), fmt.Errorf("Dictionary[%d](%q) key: %w", i, key, br.Err())
+	}
+	e.Key = key
+	var typ byte
+	if br.Number(&typ) {
+		return br.N(), fmt.Errorf("Dictionary[%d](%q) type: %w", i, key, br.Err())
+	}
+	value := newValue(Type(typ))
+	if value == nil {
+		return br.N(), fmt.Errorf("Dictionary[%d](%q) value: %w", i, key, &UnknownTypeError{Types: []Type{Type(typ)}})
+	}
+	if br.Add(pool.readValue(r, value)) {
+		return br.N(), fmt.Errorf("Dictionary[%d](%q) value: %w", i, key, br.Err())
+	}
+	*e = Entry{Key: key, Value: value}
+	return br.End()
+}
+
+// writeTo encodes e as the i-th entry of a dictionary.
+func (e Entry) writeTo(w io.Writer, i int) (n int64, err error) {
+	bw := newBinaryWriter(w)
+	if bw.String(e.Key) {
+		return bw.N(), fmt.Errorf("Dictionary[%d](%q) key: %w", i, e.Key, bw.Err())
+	}
+	if bw.Number(byte(e.Value.Type())) {
+		return bw.N(), fmt.Errorf("Dictionary[%d](%q) type: %w", i, e.Key, bw.Err())
+	}
+	if bw.Add(e.Value.WriteTo(w)) {
+		return bw.N(), fmt.Errorf("Dictionary[%d](%q) value: %w", i, e.Key, bw.Err())
+	}
+	return bw.End()
+}
+
 type ValueDictionary []Entry
 
 func (v *ValueDictionary) ReadFrom(r io.Reader) (n int64, err error) {
@@ -232,24 +465,25 @@ func (v *ValueDictionary) ReadFrom(r io.Reader) (n int64, err error) {
 	if br.Number(&length) {
 		return br.N(), fmt.Errorf("Dictionary length: %w", br.Err())
 	}
-	d := make(ValueDictionary, length)
-	for i := range d {
-		var key string
-		if br.String(&key) {
-			return br.N(), fmt.Errorf("Dictionary[%d](%q) key: %w", i, key, br.Err())
-		}
-		var typ byte
-		if br.Number(&typ) {
-			return br.N(), fmt.Errorf("Dictionary[%d](%q) type: %w", i, key, br.Err())
-		}
-		value := NewValue(Type(typ))
-		if value == nil {
-			return br.N(), fmt.Errorf("Dictionary[%d](%q) value: unknown data type 0x%02X", i, key, typ)
-		}
-		if br.Add(value.ReadFrom(r)) {
-			return br.N(), fmt.Errorf("Dictionary[%d](%q) value: %w", i, key, br.Err())
+	br.Add(v.ReadEntries(r, int(length)))
+	return br.End()
+}
+
+// ReadEntries decodes exactly count entries from r, setting v on success.
+// Unlike ReadFrom, no length prefix is read, which supports formats where the
+// number of entries is stored elsewhere.
+func (v *ValueDictionary) ReadEntries(r io.Reader, count int) (n int64, err error) {
+	if count < 0 {
+		return 0, fmt.Errorf("Dictionary: invalid length %d", count)
+	}
+	br := newBinaryReader(r)
+	d := make(ValueDictionary, 0, preallocLen(uint32(count)))
+	for i := 0; i < count; i++ {
+		var entry Entry
+		if br.Add(entry.readFrom(r, i, NewValue, nil)) {
+			return br.N(), br.Err()
 		}
-		d[i] = Entry{Key: key, Value: value}
+		d = append(d, entry)
 	}
 	*v = d
 	return br.End()
@@ -260,15 +494,18 @@ func (v ValueDictionary) WriteTo(w io.Writer) (n int64, err error) {
 	if bw.Number(uint32(len(v))) {
 		return bw.N(), fmt.Errorf("Dictionary length: %w", bw.Err())
 	}
+	bw.Add(v.WriteEntries(w))
+	return bw.End()
+}
+
+// WriteEntries encodes the entries of v to w. Unlike WriteTo, no length
+// prefix is written, which supports formats where the number of entries is
+// stored elsewhere.
+func (v ValueDictionary) WriteEntries(w io.Writer) (n int64, err error) {
+	bw := newBinaryWriter(w)
 	for i, entry := range v {
-		if bw.String(entry.Key) {
-			return bw.N(), fmt.Errorf("Dictionary[%d](%q) key: %w", i, entry.Key, bw.Err())
-		}
-		if bw.Number(byte(entry.Value.Type())) {
-			return bw.N(), fmt.Errorf("Dictionary[%d](%q) type: %w", i, entry.Key, bw.Err())
-		}
-		if bw.Add(entry.Value.WriteTo(w)) {
-			return bw.N(), fmt.Errorf("Dictionary[%d](%q) value: %w", i, entry.Key, bw.Err())
+		if bw.Add(entry.writeTo(w, i)) {
+			return bw.N(), bw.Err()
 		}
 	}
 	return bw.End()
@@ -285,6 +522,18 @@ func (ValueUDim) Type() Type {
 	return TypeUDim
 }
 
+// Valid returns whether v is a UDim that Roblox accepts, which requires Scale
+// to be finite. Offset is not restricted, as Roblox accepts the full range of
+// int32.
+func (v ValueUDim) Valid() bool {
+	return isFinite(v.Scale)
+}
+
+// isFinite returns whether f is neither NaN nor infinite.
+func isFinite(f float32) bool {
+	return !math.IsNaN(float64(f)) && !math.IsInf(float64(f), 0)
+}
+
 func (v *ValueUDim) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
 	var a ValueUDim
@@ -320,16 +569,37 @@ func (ValueUDim2) Type() Type {
 	return TypeUDim2
 }
 
+// NewUDim2 returns a UDim2 composed of the X and Y components of scale, and the
+// offsets x and y. It reverses the Scale and Offset methods.
+func NewUDim2(scale ValueVector2, x, y int32) ValueUDim2 {
+	return ValueUDim2{
+		X: ValueUDim{Scale: scale.X, Offset: x},
+		Y: ValueUDim{Scale: scale.Y, Offset: y},
+	}
+}
+
+// Scale returns the scale components of v as a Vector2.
+func (v ValueUDim2) Scale() ValueVector2 {
+	return ValueVector2{X: v.X.Scale, Y: v.Y.Scale}
+}
+
+// Offset returns the offset components of v.
+func (v ValueUDim2) Offset() (x, y int32) {
+	return v.X.Offset, v.Y.Offset
+}
+
+var udim2Fields = [...]string{"X: UDim.Scale", "X: UDim.Offset", "Y: UDim.Scale", "Y: UDim.Offset"}
+
 func (v *ValueUDim2) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
-	var a ValueUDim2
-	if br.Add((&a.X).ReadFrom(r)) {
-		return br.N(), fmt.Errorf("UDim2.X: %w", br.Err())
+	var b [16]byte
+	if br.Components(b[:]) {
+		return br.N(), fmt.Errorf("UDim2.%s: %w", udim2Fields[br.N()/4], br.Err())
 	}
-	if br.Add((&a.Y).ReadFrom(r)) {
-		return br.N(), fmt.Errorf("UDim2.Y: %w", br.Err())
+	*v = ValueUDim2{
+		X: ValueUDim{Scale: float32At(b[0:]), Offset: int32At(b[4:])},
+		Y: ValueUDim{Scale: float32At(b[8:]), Offset: int32At(b[12:])},
 	}
-	*v = a
 	return br.End()
 }
 
@@ -394,19 +664,15 @@ func (ValueColor3) Type() Type {
 	return TypeColor3
 }
 
+var color3Fields = [...]string{"R", "G", "B"}
+
 func (v *ValueColor3) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
-	var a ValueColor3
-	if br.Number(&a.R) {
-		return br.N(), fmt.Errorf("Color3.R: %w", br.Err())
-	}
-	if br.Number(&a.G) {
-		return br.N(), fmt.Errorf("Color3.G: %w", br.Err())
+	var b [12]byte
+	if br.Components(b[:]) {
+		return br.N(), fmt.Errorf("Color3.%s: %w", color3Fields[br.N()/4], br.Err())
 	}
-	if br.Number(&a.B) {
-		return br.N(), fmt.Errorf("Color3.B: %w", br.Err())
-	}
-	*v = a
+	*v = ValueColor3{R: float32At(b[0:]), G: float32At(b[4:]), B: float32At(b[8:])}
 	return br.End()
 }
 
@@ -431,20 +697,31 @@ type ValueVector2 struct {
 	Y float32
 }
 
+// NewVector2 returns a Vector2 with the given components, or an error if a
+// component is NaN or infinite.
+func NewVector2(x, y float32) (ValueVector2, error) {
+	if !isFinite(x) {
+		return ValueVector2{}, fmt.Errorf("Vector2.X: non-finite value %v", x)
+	}
+	if !isFinite(y) {
+		return ValueVector2{}, fmt.Errorf("Vector2.Y: non-finite value %v", y)
+	}
+	return ValueVector2{X: x, Y: y}, nil
+}
+
 func (ValueVector2) Type() Type {
 	return TypeVector2
 }
 
+var vectorFields = [...]string{"X", "Y", "Z"}
+
 func (v *ValueVector2) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
-	var a ValueVector2
-	if br.Number(&a.X) {
-		return br.N(), fmt.Errorf("Vector2.X: %w", br.Err())
-	}
-	if br.Number(&a.Y) {
-		return br.N(), fmt.Errorf("Vector2.Y: %w", br.Err())
+	var b [8]byte
+	if br.Components(b[:]) {
+		return br.N(), fmt.Errorf("Vector2.%s: %w", vectorFields[br.N()/4], br.Err())
 	}
-	*v = a
+	*v = ValueVector2{X: float32At(b[0:]), Y: float32At(b[4:])}
 	return br.End()
 }
 
@@ -467,23 +744,32 @@ type ValueVector3 struct {
 	Z float32
 }
 
+// NewVector3 returns a Vector3 with the given components, or an error if a
+// component is NaN or infinite.
+func NewVector3(x, y, z float32) (ValueVector3, error) {
+	if !isFinite(x) {
+		return ValueVector3{}, fmt.Errorf("Vector3.X: non-finite value %v", x)
+	}
+	if !isFinite(y) {
+		return ValueVector3{}, fmt.Errorf("Vector3.Y: non-finite value %v", y)
+	}
+	if !isFinite(z) {
+		return ValueVector3{}, fmt.Errorf("Vector3.Z: non-finite value %v", z)
+	}
+	return ValueVector3{X: x, Y: y, Z: z}, nil
+}
+
 func (ValueVector3) Type() Type {
 	return TypeVector3
 }
 
 func (v *ValueVector3) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
-	var a ValueVector3
-	if br.Number(&a.X) {
-		return br.N(), fmt.Errorf("Vector3.X: %w", br.Err())
-	}
-	if br.Number(&a.Y) {
-		return br.N(), fmt.Errorf("Vector3.Y: %w", br.Err())
-	}
-	if br.Number(&a.Z) {
-		return br.N(), fmt.Errorf("Vector3.Z: %w", br.Err())
+	var b [12]byte
+	if br.Components(b[:]) {
+		return br.N(), fmt.Errorf("Vector3.%s: %w", vectorFields[br.N()/4], br.Err())
 	}
-	*v = a
+	*v = ValueVector3{X: float32At(b[0:]), Y: float32At(b[4:]), Z: float32At(b[8:])}
 	return br.End()
 }
 
@@ -573,6 +859,10 @@ var cframeIDNumber = map[[9]float32]uint8{
 	cframeIDMatrix[0x23]: 0x23,
 }
 
+// ValueCFrame is a position and rotation. A rotation that is one of the 24
+// axis-aligned orientations is encoded as an ID rather than as a matrix, and
+// an unknown ID decodes as the identity rotation, so the encoding of a decoded
+// CFrame may differ from the original bytes.
 type ValueCFrame struct {
 	Position ValueVector3
 	Rotation [9]float32
@@ -648,6 +938,9 @@ func (v *ValueNumberSequence) ReadFrom(r io.Reader) (n int64, err error) {
 	if br.Number(&length) {
 		return br.N(), fmt.Errorf("NumberSequence length: %w", br.Err())
 	}
+	if rem, ok := unreadLen(r); ok && int64(length)*keypointSizes[TypeNumberSequence] > rem {
+		return br.N(), fmt.Errorf("NumberSequence length: %d keypoints exceed %d remaining bytes: %w", length, rem, io.ErrUnexpectedEOF)
+	}
 	s := make(ValueNumberSequence, length)
 	for i := range s {
 		var k ValueNumberSequenceKeypoint
@@ -681,19 +974,15 @@ type ValueNumberSequenceKeypoint struct {
 	Value    float32
 }
 
+var numberKeypointFields = [...]string{"Envelope", "Time", "Value"}
+
 func (v *ValueNumberSequenceKeypoint) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
-	var a ValueNumberSequenceKeypoint
-	if br.Number(&a.Envelope) {
-		return br.N(), fmt.Errorf("NumberSequenceKeypoint.Envelope: %w", br.Err())
-	}
-	if br.Number(&a.Time) {
-		return br.N(), fmt.Errorf("NumberSequenceKeypoint.Time: %w", br.Err())
-	}
-	if br.Number(&a.Value) {
-		return br.N(), fmt.Errorf("NumberSequenceKeypoint.Value: %w", br.Err())
+	var b [12]byte
+	if br.Components(b[:]) {
+		return br.N(), fmt.Errorf("NumberSequenceKeypoint.%s: %w", numberKeypointFields[br.N()/4], br.Err())
 	}
-	*v = a
+	*v = ValueNumberSequenceKeypoint{Envelope: float32At(b[0:]), Time: float32At(b[4:]), Value: float32At(b[8:])}
 	return br.End()
 }
 
@@ -725,6 +1014,9 @@ func (v *ValueColorSequence) ReadFrom(r io.Reader) (n int64, err error) {
 	if br.Number(&length) {
 		return br.N(), fmt.Errorf("ColorSequence length: %w", br.Err())
 	}
+	if rem, ok := unreadLen(r); ok && int64(length)*keypointSizes[TypeColorSequence] > rem {
+		return br.N(), fmt.Errorf("ColorSequence length: %d keypoints exceed %d remaining bytes: %w", length, rem, io.ErrUnexpectedEOF)
+	}
 	s := make(ValueColorSequence, length)
 	for i := range s {
 		var k ValueColorSequenceKeypoint
@@ -758,19 +1050,19 @@ type ValueColorSequenceKeypoint struct {
 	Value    ValueColor3
 }
 
+var colorKeypointFields = [...]string{"Envelope", "Time", "Value: Color3.R", "Value: Color3.G", "Value: Color3.B"}
+
 func (v *ValueColorSequenceKeypoint) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
-	var a ValueColorSequenceKeypoint
-	if br.Number(&a.Envelope) {
-		return br.N(), fmt.Errorf("ColorSequenceKeypoint.Envelope: %w", br.Err())
+	var b [20]byte
+	if br.Components(b[:]) {
+		return br.N(), fmt.Errorf("ColorSequenceKeypoint.%s: %w", colorKeypointFields[br.N()/4], br.Err())
 	}
-	if br.Number(&a.Time) {
-		return br.N(), fmt.Errorf("ColorSequenceKeypoint.Time: %w", br.Err())
+	*v = ValueColorSequenceKeypoint{
+		Envelope: float32At(b[0:]),
+		Time:     float32At(b[4:]),
+		Value:    ValueColor3{R: float32At(b[8:]), G: float32At(b[12:]), B: float32At(b[16:])},
 	}
-	if br.Add((&a.Value).ReadFrom(r)) {
-		return br.N(), fmt.Errorf("ColorSequenceKeypoint.Value: %w", br.Err())
-	}
-	*v = a
 	return br.End()
 }
 
@@ -834,16 +1126,18 @@ func (ValueRect) Type() Type {
 	return TypeRect
 }
 
+var rectFields = [...]string{"Min: Vector2.X", "Min: Vector2.Y", "Max: Vector2.X", "Max: Vector2.Y"}
+
 func (v *ValueRect) ReadFrom(r io.Reader) (n int64, err error) {
 	br := newBinaryReader(r)
-	var a ValueRect
-	if br.Add((&a.Min).ReadFrom(r)) {
-		return br.N(), fmt.Errorf("Rect.Min: %w", br.Err())
+	var b [16]byte
+	if br.Components(b[:]) {
+		return br.N(), fmt.Errorf("Rect.%s: %w", rectFields[br.N()/4], br.Err())
 	}
-	if br.Add((&a.Max).ReadFrom(r)) {
-		return br.N(), fmt.Errorf("Rect.Max: %w", br.Err())
+	*v = ValueRect{
+		Min: ValueVector2{X: float32At(b[0:]), Y: float32At(b[4:])},
+		Max: ValueVector2{X: float32At(b[8:]), Y: float32At(b[12:])},
 	}
-	*v = a
 	return br.End()
 }
 
@@ -873,3 +1167,60 @@ func (v ValueRect) WriteTo(w io.Writer) (n int64, err error) {
 ////////////////////////////////////////////////////////////////////////////////
 
 // type ValueRegion3int16 struct{}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// ValueFont is a font face, as used by the FontFace property of text objects.
+type ValueFont struct {
+	// Weight is the thickness of the font, from 100 (Thin) to 900 (Heavy).
+	// The regular weight is 400.
+	Weight uint16
+	// Style is 0 for Normal, or 1 for Italic.
+	Style uint8
+	// Family is the content ID of the font family, such as
+	// "rbxasset://fonts/families/SourceSansPro.json".
+	Family string
+	// CachedFaceID is the content ID of the font file that Family, Weight,
+	// and Style last resolved to, or empty if the font has not been resolved.
+	CachedFaceID string
+}
+
+func (ValueFont) Type() Type {
+	return TypeFont
+}
+
+func (v *ValueFont) ReadFrom(r io.Reader) (n int64, err error) {
+	br := newBinaryReader(r)
+	var a ValueFont
+	if br.Number(&a.Weight) {
+		return br.N(), fmt.Errorf("Font.Weight: %w", br.Err())
+	}
+	if br.Number(&a.Style) {
+		return br.N(), fmt.Errorf("Font.Style: %w", br.Err())
+	}
+	if br.String(&a.Family) {
+		return br.N(), fmt.Errorf("Font.Family: %w", br.Err())
+	}
+	if br.String(&a.CachedFaceID) {
+		return br.N(), fmt.Errorf("Font.CachedFaceID: %w", br.Err())
+	}
+	*v = a
+	return br.End()
+}
+
+func (v ValueFont) WriteTo(w io.Writer) (n int64, err error) {
+	bw := newBinaryWriter(w)
+	if bw.Number(v.Weight) {
+		return bw.N(), fmt.Errorf("Font.Weight: %w", bw.Err())
+	}
+	if bw.Number(v.Style) {
+		return bw.N(), fmt.Errorf("Font.Style: %w", bw.Err())
+	}
+	if bw.String(v.Family) {
+		return bw.N(), fmt.Errorf("Font.Family: %w", bw.Err())
+	}
+	if bw.String(v.CachedFaceID) {
+		return bw.N(), fmt.Errorf("Font.CachedFaceID: %w", bw.Err())
+	}
+	return bw.End()
+}
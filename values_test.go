@@ -0,0 +1,333 @@
+package rbxattr_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/robloxapi/rbxattr"
+)
+
+var errWriteFailed = errors.New("write failed")
+
+// failingWriter accepts up to Limit bytes, then fails. If Eager is set, the
+// write that reaches the limit reports the error even when all of its bytes
+// were accepted.
+type failingWriter struct {
+	Limit int64
+	Eager bool
+	n     int64
+}
+
+func (w *failingWriter) Write(p []byte) (n int, err error) {
+	if w.n+int64(len(p)) < w.Limit || !w.Eager && w.n+int64(len(p)) == w.Limit {
+		w.n += int64(len(p))
+		return len(p), nil
+	}
+	n = int(w.Limit - w.n)
+	w.n = w.Limit
+	return n, errWriteFailed
+}
+
+// shortWriter accepts up to Limit bytes, then reports a short write without
+// an error.
+type shortWriter struct {
+	Limit int64
+	n     int64
+}
+
+func (w *shortWriter) Write(p []byte) (n int, err error) {
+	n = len(p)
+	if w.n+int64(n) > w.Limit {
+		n = int(w.Limit - w.n)
+	}
+	w.n += int64(n)
+	return n, nil
+}
+
+func TestDictionaryWriteToFailure(t *testing.T) {
+	s := rbxattr.ValueString("foobar")
+	b := rbxattr.ValueBool(true)
+	dict := rbxattr.ValueDictionary{
+		{Key: "A", Value: &s},
+		{Key: "B", Value: &b},
+		{Key: "C", Value: &rbxattr.ValueVector3{X: 1, Y: 2, Z: 3}},
+		{Key: "D", Value: &rbxattr.ValueUDim2{}},
+		{Key: "E", Value: &b},
+	}
+	var sb strings.Builder
+	total, err := dict.WriteTo(&sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Length prefix, then three entries.
+	third := int64(4 + (5 + 1 + 10) + (5 + 1 + 1) + (5 + 1 + 12))
+
+	for _, eager := range []bool{false, true} {
+		for limit := int64(0); limit < total; limit++ {
+			w := &failingWriter{Limit: limit, Eager: eager}
+			n, err := dict.WriteTo(w)
+			if !errors.Is(err, errWriteFailed) {
+				t.Fatalf("limit %d, eager %t: expected write error, got %v", limit, eager, err)
+			}
+			if n != limit {
+				t.Fatalf("limit %d, eager %t: expected %d bytes written, got %d", limit, eager, limit, n)
+			}
+		}
+		for _, limit := range []int64{third + 1, third + 5, third + 6, third + 21} {
+			_, err := dict.WriteTo(&failingWriter{Limit: limit, Eager: eager})
+			if !strings.HasPrefix(err.Error(), `Dictionary[3]("D")`) {
+				t.Errorf("limit %d, eager %t: expected error naming fourth entry, got %q", limit, eager, err)
+			}
+		}
+	}
+
+	for limit := int64(0); limit < total; limit++ {
+		n, err := dict.WriteTo(&shortWriter{Limit: limit})
+		if !errors.Is(err, io.ErrShortWrite) {
+			t.Fatalf("limit %d: expected short write error, got %v", limit, err)
+		}
+		if n != limit {
+			t.Fatalf("limit %d: expected %d bytes written, got %d", limit, limit, n)
+		}
+	}
+}
+
+func TestDictionaryReservedType(t *testing.T) {
+	for typ, msg := range map[byte]string{
+		0x16: `Dictionary[0]("A") value: reserved type 0x16 not yet understood`,
+		0x1E: `Dictionary[0]("A") value: reserved type 0x1E not yet understood`,
+		0x40: `Dictionary[0]("A") value: unknown data type 0x40`,
+	} {
+		data := []byte{1, 0, 0, 0, 1, 0, 0, 0, 'A', typ, 0, 0, 0, 0}
+		var d rbxattr.ValueDictionary
+		_, err := d.ReadFrom(strings.NewReader(string(data)))
+		var unknown *rbxattr.UnknownTypeError
+		if !errors.As(err, &unknown) {
+			t.Fatalf("type 0x%02X: expected UnknownTypeError, got %v", typ, err)
+		}
+		if err.Error() != msg {
+			t.Errorf("type 0x%02X: unexpected error message %q", typ, err)
+		}
+	}
+}
+
+func TestBoolWriteToAllocs(t *testing.T) {
+	for _, v := range []rbxattr.ValueBool{false, true} {
+		var buf strings.Builder
+		if _, err := v.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		want := "\x00"
+		if v {
+			want = "\x01"
+		}
+		if buf.String() != want {
+			t.Fatalf("%t: expected %q, got %q", v, want, buf.String())
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			v.WriteTo(ioutil.Discard)
+		})
+		if allocs != 0 {
+			t.Errorf("%t: expected no allocations, got %v", v, allocs)
+		}
+	}
+}
+
+// exportedTypes contains every exported Type constant.
+var exportedTypes = map[string]rbxattr.Type{
+	"TypeString":         rbxattr.TypeString,
+	"TypeBool":           rbxattr.TypeBool,
+	"TypeFloat":          rbxattr.TypeFloat,
+	"TypeDouble":         rbxattr.TypeDouble,
+	"TypeUDim":           rbxattr.TypeUDim,
+	"TypeUDim2":          rbxattr.TypeUDim2,
+	"TypeBrickColor":     rbxattr.TypeBrickColor,
+	"TypeColor3":         rbxattr.TypeColor3,
+	"TypeVector2":        rbxattr.TypeVector2,
+	"TypeVector3":        rbxattr.TypeVector3,
+	"TypeCFrame":         rbxattr.TypeCFrame,
+	"TypeNumberSequence": rbxattr.TypeNumberSequence,
+	"TypeColorSequence":  rbxattr.TypeColorSequence,
+	"TypeNumberRange":    rbxattr.TypeNumberRange,
+	"TypeRect":           rbxattr.TypeRect,
+	"TypeFont":           rbxattr.TypeFont,
+}
+
+func TestTypeConstants(t *testing.T) {
+	seen := map[rbxattr.Type]string{}
+	for name, typ := range exportedTypes {
+		if other, ok := seen[typ]; ok {
+			t.Errorf("%s and %s both have value 0x%02X", name, other, byte(typ))
+		}
+		seen[typ] = name
+		v := rbxattr.NewValue(typ)
+		if v == nil {
+			t.Errorf("%s: NewValue returned nil", name)
+		} else if v.Type() != typ {
+			t.Errorf("%s: NewValue returned value of type %s", name, v.Type())
+		}
+		if "Type"+typ.String() != name {
+			t.Errorf("%s: unexpected name %q", name, typ.String())
+		}
+	}
+}
+
+func TestNewVector(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(-1))
+
+	if v, err := rbxattr.NewVector3(1, -2, 3.5); err != nil {
+		t.Errorf("Vector3: unexpected error: %s", err)
+	} else if v != (rbxattr.ValueVector3{X: 1, Y: -2, Z: 3.5}) {
+		t.Errorf("Vector3: unexpected value %v", v)
+	}
+	if _, err := rbxattr.NewVector3(1, nan, 3); err == nil {
+		t.Error("Vector3: expected error for NaN component")
+	} else if want := "Vector3.Y: non-finite value NaN"; err.Error() != want {
+		t.Errorf("Vector3: expected error %q, got %q", want, err)
+	}
+
+	if v, err := rbxattr.NewVector2(0, 1); err != nil {
+		t.Errorf("Vector2: unexpected error: %s", err)
+	} else if v != (rbxattr.ValueVector2{X: 0, Y: 1}) {
+		t.Errorf("Vector2: unexpected value %v", v)
+	}
+	if _, err := rbxattr.NewVector2(inf, 0); err == nil {
+		t.Error("Vector2: expected error for infinite component")
+	}
+}
+
+func TestFixedSizeReadErrors(t *testing.T) {
+	for _, c := range []struct {
+		value io.ReaderFrom
+		size  int
+		want  string
+	}{
+		{new(rbxattr.ValueVector3), 4, "Vector3.Y: EOF"},
+		{new(rbxattr.ValueVector3), 10, "Vector3.Z: unexpected EOF"},
+		{new(rbxattr.ValueUDim2), 13, "UDim2.Y: UDim.Offset: unexpected EOF"},
+		{new(rbxattr.ValueRect), 8, "Rect.Max: Vector2.X: EOF"},
+		{new(rbxattr.ValueColorSequenceKeypoint), 9, "ColorSequenceKeypoint.Value: Color3.R: unexpected EOF"},
+	} {
+		n, err := c.value.ReadFrom(strings.NewReader(strings.Repeat("\x00", c.size)))
+		if err == nil {
+			t.Errorf("%q: expected error", c.want)
+			continue
+		}
+		if err.Error() != c.want {
+			t.Errorf("expected error %q, got %q", c.want, err)
+		}
+		if n != int64(c.size) {
+			t.Errorf("%q: expected %d bytes read, got %d", c.want, c.size, n)
+		}
+	}
+}
+
+func TestSequenceOverlongLength(t *testing.T) {
+	// Declares 1000 keypoints, followed by one keypoint.
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(1000))
+	buf.Write(make([]byte, 20))
+	data := buf.Bytes()
+
+	for _, c := range []struct {
+		value rbxattr.Value
+		want  string
+	}{
+		{new(rbxattr.ValueNumberSequence), "NumberSequence length: 1000 keypoints exceed 20 remaining bytes: unexpected EOF"},
+		{new(rbxattr.ValueColorSequence), "ColorSequence length: 1000 keypoints exceed 20 remaining bytes: unexpected EOF"},
+	} {
+		n, err := c.value.ReadFrom(bytes.NewReader(data))
+		if err == nil || err.Error() != c.want {
+			t.Errorf("expected error %q, got %v", c.want, err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: expected ErrUnexpectedEOF", c.value.Type())
+		}
+		if n != 4 {
+			t.Errorf("%s: expected 4 bytes read, got %d", c.value.Type(), n)
+		}
+	}
+
+	// Without a known length, the error occurs at the first missing keypoint.
+	var seq rbxattr.ValueColorSequence
+	_, err := seq.ReadFrom(io.MultiReader(bytes.NewReader(data)))
+	const want = "ColorSequence[1]: ColorSequenceKeypoint.Envelope: EOF"
+	if err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+}
+
+func TestUDim2ScaleOffset(t *testing.T) {
+	v := rbxattr.ValueUDim2{
+		X: rbxattr.ValueUDim{Scale: 0.5, Offset: 100},
+		Y: rbxattr.ValueUDim{Scale: 0.25, Offset: -50},
+	}
+	if scale := v.Scale(); scale != (rbxattr.ValueVector2{X: v.X.Scale, Y: v.Y.Scale}) {
+		t.Errorf("unexpected scale %v", scale)
+	}
+	if x, y := v.Offset(); x != v.X.Offset || y != v.Y.Offset {
+		t.Errorf("unexpected offset %d, %d", x, y)
+	}
+	x, y := v.Offset()
+	if u := rbxattr.NewUDim2(v.Scale(), x, y); u != v {
+		t.Errorf("expected %v, got %v", v, u)
+	}
+}
+
+func TestFont(t *testing.T) {
+	v := rbxattr.ValueFont{
+		Weight:       700,
+		Style:        1,
+		Family:       "rbxasset://fonts/families/Arial.json",
+		CachedFaceID: "rbxasset://fonts/arialbi.ttf",
+	}
+	var want bytes.Buffer
+	binary.Write(&want, binary.LittleEndian, uint16(700))
+	want.WriteByte(1)
+	binary.Write(&want, binary.LittleEndian, uint32(len(v.Family)))
+	want.WriteString(v.Family)
+	binary.Write(&want, binary.LittleEndian, uint32(len(v.CachedFaceID)))
+	want.WriteString(v.CachedFaceID)
+
+	var buf bytes.Buffer
+	n, err := v.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(want.Len()) || !bytes.Equal(buf.Bytes(), want.Bytes()) {
+		t.Fatalf("expected bytes %X, got %X", want.Bytes(), buf.Bytes())
+	}
+
+	var got rbxattr.ValueFont
+	if n, err := got.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	} else if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes read, got %d", buf.Len(), n)
+	}
+	if got != v {
+		t.Errorf("expected %+v, got %+v", v, got)
+	}
+
+	for _, c := range []struct {
+		size int
+		want string
+	}{
+		{1, "Font.Weight: unexpected EOF"},
+		{2, "Font.Style: EOF"},
+		{10, "Font.Family: unexpected EOF"},
+		{buf.Len() - 1, "Font.CachedFaceID: unexpected EOF"},
+	} {
+		var f rbxattr.ValueFont
+		_, err := f.ReadFrom(bytes.NewReader(buf.Bytes()[:c.size]))
+		if err == nil || err.Error() != c.want {
+			t.Errorf("truncated to %d: expected error %q, got %v", c.size, c.want, err)
+		}
+	}
+}